@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Quantity rules for a product that is in both the guest cart and the buyer's
+// cart when they are merged on sign in
+const (
+	CartMergeSum   = "sum"   // Add both quantities
+	CartMergeMax   = "max"   // Keep the larger quantity
+	CartMergeGuest = "guest" // Keep the guest cart's quantity
+	CartMergeBuyer = "buyer" // Keep the buyer cart's quantity
+)
+
+const (
+	defaultCartMergeRule = CartMergeSum
+	defaultGuestCartTTL  = 30 * 24 * time.Hour
+)
+
+// CartMergeRule is the quantity rule used when a guest cart is merged,
+// read from CART_MERGE_RULE as sum, max, guest or buyer
+func CartMergeRule() string {
+	value := os.Getenv("CART_MERGE_RULE")
+	switch value {
+	case "":
+		return defaultCartMergeRule
+	case CartMergeSum, CartMergeMax, CartMergeGuest, CartMergeBuyer:
+		return value
+	}
+
+	log.Printf("invalid CART_MERGE_RULE %q, using %s", value, defaultCartMergeRule)
+	return defaultCartMergeRule
+}
+
+// GuestCartTTL is how long an untouched guest cart is kept, read from
+// GUEST_CART_TTL as a Go duration such as 720h
+func GuestCartTTL() time.Duration {
+	value := os.Getenv("GUEST_CART_TTL")
+	if value == "" {
+		return defaultGuestCartTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Printf("invalid GUEST_CART_TTL %q, using %s", value, defaultGuestCartTTL)
+		return defaultGuestCartTTL
+	}
+	return ttl
+}
@@ -31,21 +31,189 @@ func ConnectDB() {
 		log.Fatalf("Failed to connect to the database: %v", err)
 	}
 
+	// Stock and prices from before the ledger and price history existed get
+	// opening entries, once
+	hadStockLedger := db.Migrator().HasTable(&models.StockMovement{})
+	hadPriceHistory := db.Migrator().HasTable(&models.ProductPriceChange{})
+	hadRevisions := db.Migrator().HasTable(&models.ProductRevision{})
+	hadMediaReferences := db.Migrator().HasTable(&models.MediaReference{})
+	hadCartItemPrices := db.Migrator().HasColumn(&models.CartItem{}, "unit_price")
+	hadOrderBreakdown := db.Migrator().HasColumn(&models.Order{}, "subtotal")
+	hadProductStatus := db.Migrator().HasColumn(&models.Product{}, "status")
+
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.Profile{},
 		&models.Product{},
+		&models.BundleComponent{},
+		&models.ProductSlugRedirect{},
+		&models.ProductRevision{},
+		&models.ProductImage{},
 		&models.Category{},
+		&models.CategoryAttribute{},
+		&models.ProductAttribute{},
 		&models.AuditLog{},
+		&models.ProductImportJob{},
 		&models.Order{},
 		&models.OrderItem{},
 		&models.Cart{},
 		&models.CartItem{},
+		&models.WishlistItem{},
+		&models.RecentlyViewedProduct{},
+		&models.ProductReview{},
+		&models.ProductReviewPhoto{},
+		&models.MediaObject{},
+		&models.MediaReference{},
+		&models.Upload{},
+		&models.ReviewHelpfulVote{},
+		&models.StockMovement{},
+		&models.StockReservation{},
+		&models.ProductSalePrice{},
+		&models.ProductPriceChange{},
+		&models.ProductAffinity{},
+		&models.ProductSalesStat{},
+		&models.RecommendationProcessedOrder{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database schema:", err)
 	}
 
+	// Order items used to cascade on product deletion, which wiped order history
+	var onDelete string
+	db.Raw("SELECT confdeltype FROM pg_constraint WHERE conname = ?", "fk_order_items_product").Scan(&onDelete)
+	if onDelete == "c" {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropConstraint(&models.OrderItem{}, "Product"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateConstraint(&models.OrderItem{}, "Product")
+		})
+		if err != nil {
+			log.Fatal("Failed to migrate order item constraint:", err)
+		}
+	}
+
+	// Products created before galleries existed only have products.image_url
+	err = db.Exec(`
+		INSERT INTO product_images (product_id, url, alt_text, position, is_primary, created_at, updated_at)
+		SELECT products.id, products.image_url, products.name, 0, true, NOW(), NOW()
+		FROM products
+		WHERE products.image_url <> ''
+			AND NOT EXISTS (SELECT 1 FROM product_images WHERE product_images.product_id = products.id)`).Error
+	if err != nil {
+		log.Fatal("Failed to backfill product images:", err)
+	}
+
+	// Images from before renditions existed serve the original at every size
+	for _, table := range []string{"product_images", "product_review_photos"} {
+		if err := db.Exec("UPDATE " + table + " SET medium_url = url, thumbnail_url = url WHERE thumbnail_url = ''").Error; err != nil {
+			log.Fatal("Failed to backfill image renditions:", err)
+		}
+	}
+
+	if !hadStockLedger {
+		err = db.Exec(`
+			INSERT INTO stock_movements (product_id, delta, reason, reference_id, note, balance_after, created_at)
+			SELECT products.id, products.stock, ?, 'opening-balance', 'Stock held before the ledger existed', products.stock, NOW()
+			FROM products
+			WHERE products.stock <> 0`, models.StockReasonAdjustment).Error
+		if err != nil {
+			log.Fatal("Failed to record opening stock balances:", err)
+		}
+	}
+
+	// Price history starts from the price products have today
+	if !hadPriceHistory {
+		err = db.Exec(`
+			INSERT INTO product_price_changes (product_id, type, old_price, new_price, created_at)
+			SELECT products.id, ?, products.price, products.price, NOW()
+			FROM products`, models.PriceChangeRegular).Error
+		if err != nil {
+			log.Fatal("Failed to record opening prices:", err)
+		}
+	}
+
+	// Products from before statuses existed were all live, and the new column
+	// just gave them the draft default. Those from before the publishing
+	// workflow were live as "active".
+	liveProducts := db.Unscoped().Model(&models.Product{})
+	if hadProductStatus {
+		liveProducts = liveProducts.Where("status = ?", "active")
+	} else {
+		liveProducts = liveProducts.Where("1 = 1")
+	}
+	if err := liveProducts.UpdateColumns(map[string]interface{}{
+		"status":       models.ProductStatusPublished,
+		"published_at": gorm.Expr("created_at"),
+	}).Error; err != nil {
+		log.Fatal("Failed to migrate product statuses:", err)
+	}
+
+	// Products created before slugs existed get one from their name
+	var unslugged []models.Product
+	if err := db.Unscoped().Select("id", "name").Where("slug = ''").Order("id").Find(&unslugged).Error; err != nil {
+		log.Fatal("Failed to look up products without slug:", err)
+	}
+	for i := range unslugged {
+		if err := models.AssignProductSlug(db, &unslugged[i]); err != nil {
+			log.Fatal("Failed to backfill product slugs:", err)
+		}
+	}
+
+	// Revisions start from the state products are in today
+	if !hadRevisions {
+		var productIDs []uint64
+		if err := db.Unscoped().Model(&models.Product{}).Order("id").Pluck("id", &productIDs).Error; err != nil {
+			log.Fatal("Failed to look up products for revisions:", err)
+		}
+		for _, productID := range productIDs {
+			if err := models.RecordProductRevision(db, productID, models.RevisionBaseline, nil, nil); err != nil {
+				log.Fatal("Failed to record baseline revisions:", err)
+			}
+		}
+	}
+
+	// Media stored before references were tracked is matched to the images
+	// and photos using it by its key, which ends every public URL
+	if !hadMediaReferences {
+		for table, column := range map[string]string{
+			"product_images":        "product_image_id",
+			"product_review_photos": "review_photo_id",
+		} {
+			err = db.Exec(`
+				INSERT INTO media_references (media_object_id, ` + column + `, created_at)
+				SELECT media_objects.id, ` + table + `.id, NOW()
+				FROM media_objects
+				JOIN ` + table + ` ON ` + table + `.url LIKE '%/' || media_objects.key
+					OR ` + table + `.medium_url LIKE '%/' || media_objects.key
+					OR ` + table + `.thumbnail_url LIKE '%/' || media_objects.key`).Error
+			if err != nil {
+				log.Fatal("Failed to backfill media references:", err)
+			}
+		}
+	}
+
+	// Cart items from before price snapshots are taken as added at today's price
+	if !hadCartItemPrices {
+		err = db.Exec(`
+			UPDATE cart_items SET unit_price = current.price
+			FROM (
+				SELECT products.id, ` + models.EffectivePriceSQL + ` AS price
+				FROM products ` + models.ActiveSaleJoin + `
+			) AS current
+			WHERE current.id = cart_items.product_id`).Error
+		if err != nil {
+			log.Fatal("Failed to backfill cart item prices:", err)
+		}
+	}
+
+	// Orders placed before the breakdown was stored were charged their items only
+	if !hadOrderBreakdown {
+		if err := db.Exec("UPDATE orders SET subtotal = total_amount").Error; err != nil {
+			log.Fatal("Failed to backfill order subtotals:", err)
+		}
+	}
+
 	DB = db
 
 	log.Println("Successfully connected to the database!")
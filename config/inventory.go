@@ -0,0 +1,25 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+const defaultReservationTTL = 15 * time.Minute
+
+// ReservationTTL is how long checkout holds stock, read from
+// STOCK_RESERVATION_TTL as a Go duration such as 15m
+func ReservationTTL() time.Duration {
+	value := os.Getenv("STOCK_RESERVATION_TTL")
+	if value == "" {
+		return defaultReservationTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Printf("invalid STOCK_RESERVATION_TTL %q, using %s", value, defaultReservationTTL)
+		return defaultReservationTTL
+	}
+	return ttl
+}
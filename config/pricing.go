@@ -0,0 +1,41 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// Pricing holds what a cart is charged on top of its products
+type Pricing struct {
+	ShippingBaseRate      float64 // Charged once per order
+	ShippingRatePerKG     float64 // Charged on top of the base rate by product weight
+	FreeShippingThreshold float64 // Orders of at least this much after discounts ship free, 0 disables
+	TaxRate               float64 // Percentage of the discounted subtotal
+}
+
+// CartPricing reads the pricing settings from SHIPPING_BASE_RATE,
+// SHIPPING_RATE_PER_KG, FREE_SHIPPING_THRESHOLD and TAX_RATE, all 0 when unset
+func CartPricing() Pricing {
+	return Pricing{
+		ShippingBaseRate:      envAmount("SHIPPING_BASE_RATE"),
+		ShippingRatePerKG:     envAmount("SHIPPING_RATE_PER_KG"),
+		FreeShippingThreshold: envAmount("FREE_SHIPPING_THRESHOLD"),
+		TaxRate:               envAmount("TAX_RATE"),
+	}
+}
+
+// envAmount reads a non-negative amount from the environment, 0 when unset or invalid
+func envAmount(name string) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		log.Printf("invalid %s %q, using 0", name, value)
+		return 0
+	}
+	return amount
+}
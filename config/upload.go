@@ -0,0 +1,69 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxImageUploadMB = 10
+	defaultMediaGCGrace     = 24 * time.Hour
+	defaultUploadURLTTL     = 15 * time.Minute
+)
+
+// MaxImageUploadBytes is the largest image accepted for upload, read from
+// IMAGE_MAX_UPLOAD_MB in megabytes
+func MaxImageUploadBytes() int64 {
+	value := os.Getenv("IMAGE_MAX_UPLOAD_MB")
+	if value == "" {
+		return defaultMaxImageUploadMB << 20
+	}
+
+	mb, err := strconv.Atoi(value)
+	if err != nil || mb <= 0 {
+		log.Printf("invalid IMAGE_MAX_UPLOAD_MB %q, using %d", value, defaultMaxImageUploadMB)
+		return defaultMaxImageUploadMB << 20
+	}
+	return int64(mb) << 20
+}
+
+// MediaGCGrace is how long stored media stays orphaned before it is deleted,
+// read from MEDIA_GC_GRACE as a Go duration such as 24h
+func MediaGCGrace() time.Duration {
+	value := os.Getenv("MEDIA_GC_GRACE")
+	if value == "" {
+		return defaultMediaGCGrace
+	}
+
+	grace, err := time.ParseDuration(value)
+	if err != nil || grace < 0 {
+		log.Printf("invalid MEDIA_GC_GRACE %q, using %s", value, defaultMediaGCGrace)
+		return defaultMediaGCGrace
+	}
+	return grace
+}
+
+// MediaGCDryRun makes media garbage collection only report what it would
+// delete, set through MEDIA_GC_DRY_RUN=true
+func MediaGCDryRun() bool {
+	dryRun, _ := strconv.ParseBool(os.Getenv("MEDIA_GC_DRY_RUN"))
+	return dryRun
+}
+
+// UploadURLTTL is how long a signed direct upload URL stays valid, read from
+// UPLOAD_URL_TTL as a Go duration such as 15m
+func UploadURLTTL() time.Duration {
+	value := os.Getenv("UPLOAD_URL_TTL")
+	if value == "" {
+		return defaultUploadURLTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Printf("invalid UPLOAD_URL_TTL %q, using %s", value, defaultUploadURLTTL)
+		return defaultUploadURLTTL
+	}
+	return ttl
+}
@@ -24,14 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/order/{id}/status": {
-            "put": {
+        "/admin/category/{id}/attributes": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin can update the status of an order (accept, reject, ontheway, finish)",
+                "description": "Retrieve the specification attributes products of a category can define",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,35 +39,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Orders"
+                    "Admin Category"
                 ],
-                "summary": "Update Order Status",
+                "summary": "Get category attribute schema",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "New order status (accept, reject, ontheway, finish)",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "status": {
-                                    "type": "string"
-                                }
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order status updated successfully",
+                        "description": "Attribute schema",
                         "schema": {
                             "allOf": [
                                 {
@@ -77,14 +63,9 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "properties": {
-                                                "order_id": {
-                                                    "type": "integer"
-                                                },
-                                                "status": {
-                                                    "type": "string"
-                                                }
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.CategoryAttributeResponse"
                                             }
                                         }
                                     }
@@ -93,28 +74,26 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request: Invalid status or order not found",
+                        "description": "Invalid category ID",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/admin/order/{order_id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve details of a specific order, accessible only to the order's buyer",
+                "description": "Admin replaces the specification attributes of a category. Values stored on products for removed attributes are deleted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,21 +101,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Orders"
+                    "Admin Category"
                 ],
-                "summary": "Get Order Items Detail",
+                "summary": "Replace category attribute schema",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Category ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Attribute schema in display order",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.UpdateCategoryAttributesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order details fetched successfully",
+                        "description": "Attribute schema",
                         "schema": {
                             "allOf": [
                                 {
@@ -146,7 +134,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/admin.OrderDetailWithItemsResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.CategoryAttributeResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -154,25 +145,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Validation Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch order details",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -180,43 +165,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/orders": {
+        "/admin/inventory/low-stock": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of orders placed by the authenticated buyer",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Admin lists products, archived ones excluded, whose stock is at or below their reorder threshold, emptiest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Orders"
+                    "Admin Inventory"
                 ],
-                "summary": "View Orders",
+                "summary": "Get low-stock products",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
+                        "description": "Page number (default: 1)",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
+                        "description": "Number of items per page (default: 25)",
                         "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of products with seller details",
+                        "description": "List of low-stock products",
                         "schema": {
                             "allOf": [
                                 {
@@ -228,7 +208,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/admin.OrderDetailWithItemsResponse"
+                                                "$ref": "#/definitions/admin.LowStockProductResponse"
                                             }
                                         }
                                     }
@@ -245,82 +225,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/product": {
-            "post": {
+        "/admin/inventory/reconciliation": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin adds a new product",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Admin checks every product, archived ones included, and lists those whose stock differs from the sum of their movements",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Product"
+                    "Admin Inventory"
                 ],
-                "summary": "Add a product",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product Name",
-                        "name": "name",
-                        "in": "formData",
-                        "required": true
+                "summary": "Reconcile stock",
+                "responses": {
+                    "200": {
+                        "description": "Reconciliation report",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.StockReconciliationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/media/orphans": {
+            "get": {
+                "security": [
                     {
-                        "type": "number",
-                        "description": "Product Price",
-                        "name": "price",
-                        "in": "formData",
-                        "required": true
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists stored media no product image or review photo references any more, oldest orphan first. It is a dry-run report of what media garbage collection deletes once the grace period has passed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Media"
+                ],
+                "summary": "Get orphaned media",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product Stock",
-                        "name": "stock",
-                        "in": "formData",
-                        "required": true
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Product Category",
-                        "name": "category_id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Product Image",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Product",
-                        "schema": {
-                            "$ref": "#/definitions/helper.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation Error",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "200": {
+                        "description": "List of orphaned media",
                         "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.OrphanedMediaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "403": {
-                        "description": "Access forbidden",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -328,14 +328,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/product/{id}": {
-            "get": {
+        "/admin/order/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a detail of products with seller",
+                "description": "Admin can update the status of an order (accept, reject, ontheway, finish). Rejecting an order returns its stock, and rejected orders can't be reopened.",
                 "consumes": [
                     "application/json"
                 ],
@@ -343,9 +343,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Product"
+                    "Admin Orders"
                 ],
-                "summary": "Get Product Detail",
+                "summary": "Update Order Status",
                 "parameters": [
                     {
                         "type": "integer",
@@ -353,11 +353,25 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New order status (accept, reject, ontheway, finish)",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of products with seller details",
+                        "description": "Order status updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -367,7 +381,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/admin.GetProductResponseComplete"
+                                            "type": "object",
+                                            "properties": {
+                                                "order_id": {
+                                                    "type": "integer"
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
                                         }
                                     }
                                 }
@@ -375,78 +397,68 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameters",
+                        "description": "Bad Request: Invalid status or order not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/order/{order_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin edit a product",
+                "description": "Retrieve details of a specific order, accessible only to the order's buyer",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Product"
+                    "Admin Orders"
                 ],
-                "summary": "Edit a product",
+                "summary": "Get Order Items Detail",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Order ID",
-                        "name": "id",
+                        "name": "order_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Product Name",
-                        "name": "name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Product Price",
-                        "name": "price",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Product Stock",
-                        "name": "stock",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Product Category",
-                        "name": "category_id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Product Image",
-                        "name": "image",
-                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product",
+                        "description": "Order details fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/helper.SuccessResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.OrderDetailWithItemsResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Validation Error",
+                        "description": "Invalid order ID",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -458,60 +470,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Access forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Admin delete a product",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin Product"
-                ],
-                "summary": "Delete a product",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Product",
-                        "schema": {
-                            "$ref": "#/definitions/helper.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation Error",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Access denied",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Access forbidden",
+                    "500": {
+                        "description": "Failed to fetch order details",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -519,14 +484,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/products": {
+        "/admin/orders": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a paginated list of products with seller details",
+                "description": "Retrieve a list of orders placed by the authenticated buyer",
                 "consumes": [
                     "application/json"
                 ],
@@ -534,39 +499,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin Product"
+                    "Admin Orders"
                 ],
-                "summary": "Get Products",
+                "summary": "View Orders",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
+                        "default": 1,
+                        "description": "Page number",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default: 25)",
+                        "default": 10,
+                        "description": "Number of items per page",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "id of seller (default: 1)",
-                        "name": "seller_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Name of seller (default: Deketna)",
-                        "name": "seller_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Name of product (default: botol)",
-                        "name": "product_name",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -583,7 +532,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/admin.GetProductResponseComplete"
+                                                "$ref": "#/definitions/admin.OrderDetailWithItemsResponse"
                                             }
                                         }
                                     }
@@ -591,276 +540,185 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid query parameters",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/signin": {
-            "post": {
-                "description": "Authenticates as admin  with email and password",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin Auth"
-                ],
-                "summary": "Sign in a admin",
-                "parameters": [
-                    {
-                        "description": "Admin sign-in data",
-                        "name": "admin",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/admin.SignInRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/admin.SignInResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/admin.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/admin.ErrorResponse"
+                            "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cart": {
-            "get": {
+        "/admin/product": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all cart items for the logged-in user",
+                "description": "Admin adds a new product. It starts as a draft, hidden from the storefront, unless it is published or scheduled.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admin Product"
                 ],
-                "summary": "Get Cart Items",
+                "summary": "Add a product",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Stock keeping unit, must be unique",
+                        "name": "sku",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product Name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Product Price",
+                        "name": "price",
+                        "in": "formData",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default: 25)",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of cart items",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/helper.PaginationResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/user.CartItemResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
+                        "description": "Product Stock, required unless the product is a bundle",
+                        "name": "stock",
+                        "in": "formData"
                     },
-                    "400": {
-                        "description": "Invalid query parameters",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Alert admins when stock falls to this level or below (default: 0, no alerts)",
+                        "name": "reorder_threshold",
+                        "in": "formData"
                     },
-                    "500": {
-                        "description": "Failed to retrieve cart items",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "security": [
                     {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update the quantity of a specific cart item",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Cart"
-                ],
-                "summary": "Update Cart Item",
-                "parameters": [
+                        "type": "integer",
+                        "description": "Product Category",
+                        "name": "category_id",
+                        "in": "formData",
+                        "required": true
+                    },
                     {
-                        "description": "Cart item details",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.UpdateCartRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Cart item updated successfully",
-                        "schema": {
-                            "$ref": "#/definitions/helper.SuccessResponse"
-                        }
+                        "type": "file",
+                        "description": "Product Image, required unless upload_id is given",
+                        "name": "image",
+                        "in": "formData"
                     },
-                    "400": {
-                        "description": "Invalid input data",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Confirmed direct upload to use as the product image",
+                        "name": "upload_id",
+                        "in": "formData"
                     },
-                    "500": {
-                        "description": "Failed to update cart item",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
                     {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Add a product with a specific quantity to the buyer's cart",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Cart"
-                ],
-                "summary": "Add Product to Cart",
-                "parameters": [
+                        "type": "string",
+                        "description": "Product description",
+                        "name": "description",
+                        "in": "formData"
+                    },
                     {
-                        "description": "Product ID and Quantity",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.AddToCartRequest"
-                        }
+                        "type": "string",
+                        "description": "Description format (markdown or html, default: markdown)",
+                        "name": "description_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand",
+                        "name": "brand",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Weight in grams",
+                        "name": "weight_grams",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Length in centimeters",
+                        "name": "length_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Width in centimeters",
+                        "name": "width_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Height in centimeters",
+                        "name": "height_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Specification attributes as a JSON object, e.g. {\\",
+                        "name": "attributes",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status (draft, scheduled or published, default: draft)",
+                        "name": "status",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product type (simple or bundle, default: simple). Bundles take their stock from their components and ignore stock.",
+                        "name": "type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Components of a bundle as a JSON array, e.g. [{\\",
+                        "name": "components",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time a scheduled product goes live, schedules the product when status is left out",
+                        "name": "publish_at",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Product added to cart successfully",
+                    "201": {
+                        "description": "Product",
                         "schema": {
                             "$ref": "#/definitions/helper.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Product not found",
+                        "description": "Validation Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete one or more items from the user's cart",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Cart"
-                ],
-                "summary": "Delete Cart Items",
-                "parameters": [
-                    {
-                        "description": "Cart Item IDs to delete",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.DeleteCartRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Cart items deleted successfully",
-                        "schema": {
-                            "$ref": "#/definitions/helper.SuccessResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Invalid input data",
+                    "403": {
+                        "description": "Access forbidden",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to delete cart items",
+                    "410": {
+                        "description": "Upload expired",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -868,14 +726,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/order": {
-            "post": {
+        "/admin/product/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order with selected products, validate stock, deduct quantities",
+                "description": "Retrieve a detail of products with seller, in any status, so admins can preview drafts, scheduled and unpublished products",
                 "consumes": [
                     "application/json"
                 ],
@@ -883,26 +741,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Orders"
+                    "Admin Product"
                 ],
-                "summary": "Place Order",
+                "summary": "Get Product Detail",
                 "parameters": [
                     {
-                        "description": "List of products and quantities",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/user.OrderItemRequest"
-                            }
-                        }
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order placed successfully",
+                        "description": "Product with seller details and gallery",
                         "schema": {
                             "allOf": [
                                 {
@@ -912,84 +765,177 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "properties": {
-                                                "order_id": {
-                                                    "type": "integer"
-                                                },
-                                                "total_amount": {
-                                                    "type": "number"
-                                                }
-                                            }
+                                            "$ref": "#/definitions/admin.GetProductDetailResponse"
                                         }
                                     }
                                 }
                             ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the product, to send back as If-Match when editing it"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Validation Error",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/order/{order_id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve details of a specific order, accessible only to the order's buyer",
+                "description": "Admin edit a product. The If-Match header must hold the ETag the product was read with, edits based on an outdated copy are refused.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Orders"
+                    "Admin Product"
                 ],
-                "summary": "Get Order Items Detail",
+                "summary": "Edit a product",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Order ID",
-                        "name": "order_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Order details fetched successfully",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/helper.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/user.OrderDetailWithItemsResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product as read from the product detail",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock keeping unit, must be unique",
+                        "name": "sku",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product Name",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Product Price",
+                        "name": "price",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Change of stock, e.g. -2 for two damaged units, recorded as an adjustment",
+                        "name": "stock_delta",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alert admins when stock falls to this level or below, 0 disables alerts",
+                        "name": "reorder_threshold",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product Category",
+                        "name": "category_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Product Image (replaces the primary gallery image)",
+                        "name": "image",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Confirmed direct upload that replaces the primary gallery image",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product description",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description format (markdown or html)",
+                        "name": "description_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand",
+                        "name": "brand",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Weight in grams",
+                        "name": "weight_grams",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Length in centimeters",
+                        "name": "length_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Width in centimeters",
+                        "name": "width_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Height in centimeters",
+                        "name": "height_cm",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Specification attributes as a JSON object, replaces all existing attributes",
+                        "name": "attributes",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status (draft, scheduled, published or unpublished)",
+                        "name": "status",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time a scheduled product goes live, schedules the product when status is left out",
+                        "name": "publish_at",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Validation Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1001,28 +947,38 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Access forbidden",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch order details",
+                    "410": {
+                        "description": "Upload expired",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Product was changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of orders placed by the authenticated buyer",
+                "description": "Admin archives a product. It disappears from the storefront but stays in order history and can be restored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1030,49 +986,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Orders"
+                    "Admin Product"
                 ],
-                "summary": "View Orders",
+                "summary": "Archive a product",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of products with seller details",
+                        "description": "Product",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/helper.PaginationResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/user.OrderResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/helper.SuccessResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Validation Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1080,19 +1032,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/{id}": {
-            "get": {
-                "description": "Retrieve details of a specific product with seller information",
+        "/admin/product/{id}/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin uploads one or more JPEG, PNG, GIF or WebP images to a product gallery. Images are appended after the existing ones. Each is stripped of its metadata and stored as WebP thumbnail, medium and large renditions.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Admin Product"
                 ],
-                "summary": "Get Product Detail",
+                "summary": "Upload product images",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1100,11 +1057,29 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Product Images (repeat the field to upload several)",
+                        "name": "images",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Confirmed direct uploads to add after the posted images (repeat the field to add several)",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alt text, one value per image in the same order, posted images first",
+                        "name": "alt_text",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Product details with seller information",
+                    "201": {
+                        "description": "Product gallery",
                         "schema": {
                             "allOf": [
                                 {
@@ -1114,7 +1089,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/user.ProductWithSeller"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.ProductImageResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -1122,7 +1100,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid Product ID",
+                        "description": "Validation Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1132,13 +1110,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
+                    },
+                    "410": {
+                        "description": "Upload expired",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/products": {
-            "get": {
-                "description": "Retrieve a paginated list of products with seller details",
+        "/admin/product/{id}/images/reorder": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin sets the display order of a product gallery and optionally picks the primary image",
                 "consumes": [
                     "application/json"
                 ],
@@ -1146,36 +1141,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Admin Product"
                 ],
-                "summary": "Get Products",
+                "summary": "Reorder product images",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page (default: 25)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Search specific product by keyword (default: ",
-                        "name": "search_product",
-                        "in": "query"
+                        "description": "Image IDs in display order",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.ReorderProductImagesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of products with seller details",
+                        "description": "Product gallery",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                    "$ref": "#/definitions/helper.SuccessResponse"
                                 },
                                 {
                                     "type": "object",
@@ -1183,7 +1176,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/user.ProductWithSeller"
+                                                "$ref": "#/definitions/admin.ProductImageResponse"
                                             }
                                         }
                                     }
@@ -1192,22 +1185,28 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameters",
+                        "description": "Validation Error",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
-                    }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "/profile": {
-            "get": {
+        "/admin/product/{id}/images/{image_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the profile of the currently authenticated user",
+                "description": "Admin updates the alt text of a gallery image or marks it as the primary image",
                 "consumes": [
                     "application/json"
                 ],
@@ -1215,12 +1214,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Profile"
+                    "Admin Product"
+                ],
+                "summary": "Update a product image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image fields to update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.UpdateProductImageRequest"
+                        }
+                    }
                 ],
-                "summary": "Get User Profile",
                 "responses": {
                     "200": {
-                        "description": "Profile retrieved successfully",
+                        "description": "Product gallery",
                         "schema": {
                             "allOf": [
                                 {
@@ -1230,15 +1254,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/user.ProfileResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.ProductImageResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Validation Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1251,13 +1284,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the profile details of the currently authenticated user",
+                "description": "Admin removes an image from a product gallery. When the primary image is removed the next image becomes primary.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1265,23 +1298,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Profile"
+                    "Admin Product"
                 ],
-                "summary": "Edit User Profile",
+                "summary": "Delete a product image",
                 "parameters": [
                     {
-                        "description": "Profile data to update",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.EditProfileRequest"
-                        }
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "Product gallery",
                         "schema": {
                             "allOf": [
                                 {
@@ -1291,7 +1329,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/user.EditProfileResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.ProductImageResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -1304,8 +1345,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Image not found",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1319,9 +1360,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Register a new user with email and password",
+        "/admin/product/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin permanently removes an archived product with its gallery and attributes. Products referenced by orders can't be purged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1329,41 +1375,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Auth"
+                    "Admin Product"
                 ],
-                "summary": "Register a new user",
+                "summary": "Permanently delete a product",
                 "parameters": [
                     {
-                        "description": "User registration data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.CreateUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Created successfully",
+                        "description": "Product purged successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/helper.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/user.SignInResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/helper.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request: Invalid input/Email is already registered",
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product is not archived, is referenced by orders or is a bundle component",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
@@ -1377,9 +1421,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/signin": {
+        "/admin/product/{id}/restore": {
             "post": {
-                "description": "Authenticates a user with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin restores an archived product in the status it had before it was archived, a draft when that is unknown",
                 "consumes": [
                     "application/json"
                 ],
@@ -1387,33 +1436,124 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Auth"
+                    "Admin Product"
                 ],
-                "summary": "Sign in a user (buyer)",
+                "summary": "Restore an archived product",
                 "parameters": [
                     {
-                        "description": "User sign-in data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product restored successfully",
                         "schema": {
-                            "$ref": "#/definitions/user.SignInRequest"
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Archived product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of products with seller details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Get Products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of seller (default: 1)",
+                        "name": "seller_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of seller (default: Deketna)",
+                        "name": "seller_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of product (default: botol)",
+                        "name": "product_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status (draft, scheduled, published, unpublished or archived). Archived products are only listed when asked for.",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Login successfully",
+                        "description": "List of products with seller details",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                    "$ref": "#/definitions/helper.PaginationResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/user.SignInResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.GetProductResponseComplete"
+                                            }
                                         }
                                     }
                                 }
@@ -1421,198 +1561,4780 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request: Invalid input",
-                        "schema": {
-                            "$ref": "#/definitions/helper.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/helper.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "admin.Category": {
-            "type": "object",
-            "properties": {
+        },
+        "/admin/products/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin exports the catalog as CSV, XLSX or JSON. The file uses the same columns as the product import, so it can be edited and imported back in any of the three formats, and is streamed row by row.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Export products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (csv, xlsx or json, default: csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of seller",
+                        "name": "seller_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of seller",
+                        "name": "seller_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of product",
+                        "name": "product_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status (draft, active or archived)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product catalog",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin imports products from a CSV, XLSX or JSON file with the columns id, sku, name, price, stock, category, image_url, brand and description, as the product export writes them. Products are matched by id, or by SKU when the id is empty: existing ones are updated, rows matching no product are created as drafts. With dry_run every row is validated and errors are returned by row without writing anything; otherwise the valid rows are imported by a background job whose progress can be polled.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Import products",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV, XLSX or JSON file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format (csv, xlsx or json), taken from the file extension by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run result",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ImportDryRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Import job started",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ImportJobResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/import/{job_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the progress and row errors of a product import job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Get product import job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Import job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import job",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ImportJobResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/components": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists the products a bundle contains, with the quantity per bundle and their stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "List the components of a bundle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bundle components retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.BundleComponentResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Bundle not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin replaces the products a bundle contains. Components must be existing products that aren't bundles themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Set the components of a bundle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Components and quantities per bundle",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SetBundleComponentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bundle components updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.BundleComponentResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bundle not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/price-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists every regular price change, scheduled sale and cancelled sale of a product, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Pricing"
+                ],
+                "summary": "Get price history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price history",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.PriceChangeResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists the revisions of a product, newest first, each with the fields it changed compared to the revision before it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Get product revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product revisions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.ProductRevisionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin rolls a product back to the fields of one of its revisions. Stock, the gallery and bundle components are left as they are. The rollback is recorded as a new revision.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Product"
+                ],
+                "summary": "Restore a product revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product, the rollback is refused when the product changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product restored to the revision",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.GetProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The revision can't be applied any more",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Product was changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/sale-prices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists the past, running and scheduled sales of a product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Pricing"
+                ],
+                "summary": "Get sale prices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sale prices",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.SalePriceResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin schedules a sale price for a product between two times. Buyers pay the sale price in that window and see the regular price as the compare-at price. Sales of a product can't overlap.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Pricing"
+                ],
+                "summary": "Schedule a sale price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sale price and period",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.CreateSalePriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Sale price scheduled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.SalePriceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlaps another sale",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/sale-prices/{sale_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin cancels a sale. A scheduled sale is removed, a running sale ends now. Ended sales can't be cancelled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Pricing"
+                ],
+                "summary": "Cancel a sale price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sale price ID",
+                        "name": "sale_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sale price cancelled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or sale already ended",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale price not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/stock-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists the inventory movements of a product, newest first. Archived products are included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Inventory"
+                ],
+                "summary": "Get stock history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only movements with this reason (sale, restock, adjustment, return or cancellation)",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of stock movements",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.StockMovementResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/stock-movements": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin records a restock, a manual adjustment or a customer return. The product stock changes by the delta.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Inventory"
+                ],
+                "summary": "Record a stock movement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock movement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.AddStockMovementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stock movement recorded successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.StockMovementResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Stock would go below zero",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/review/{id}/hide": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin hides a review from the storefront. Hidden reviews no longer count towards the product rating.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Review"
+                ],
+                "summary": "Hide review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.HideReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review hidden successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/review/{id}/unhide": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin makes a hidden review visible again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Review"
+                ],
+                "summary": "Unhide review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists product reviews, including hidden ones, for moderation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Review"
+                ],
+                "summary": "Get reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only reviews of this product",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter on hidden state",
+                        "name": "hidden",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of reviews",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/admin.AdminReviewResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/signin": {
+            "post": {
+                "description": "Authenticates as admin  with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Auth"
+                ],
+                "summary": "Sign in a admin",
+                "parameters": [
+                    {
+                        "description": "Admin sign-in data",
+                        "name": "admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.SignInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin gets a signed URL to PUT an image straight to storage, bypassing the API. The upload has to be confirmed before products can use it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Upload"
+                ],
+                "summary": "Start a direct upload",
+                "parameters": [
+                    {
+                        "description": "File to upload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.CreateUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Upload URL",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.CreateUploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/uploads/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin confirms that the file was stored through the upload URL. The file is checked to be a valid image and turned into renditions, after which products can use the upload by its ID for 24 hours. Confirming twice returns the same renditions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Upload"
+                ],
+                "summary": "Confirm a direct upload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload confirmed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.UploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Not a valid image",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "File not stored yet",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload expired or rejected",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all cart items for the logged-in user, priced at the price in effect now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get Cart Items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of cart items",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.CartItemResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve cart items",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the quantity of a specific cart item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Update Cart Item",
+                "parameters": [
+                    {
+                        "description": "Cart item details",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart item updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart item",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a product with a specific quantity to the buyer's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Add Product to Cart",
+                "parameters": [
+                    {
+                        "description": "Product ID and Quantity",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.AddToCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product added to cart successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one or more items from the user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Delete Cart Items",
+                "parameters": [
+                    {
+                        "description": "Cart Item IDs to delete",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.DeleteCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart items deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete cart items",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Products frequently bought together with the products in the buyer's cart, filled with best-sellers of the same categories when there isn't enough order data",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get Cart Recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations (default: 8, max: 24)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommended products",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.RecommendedProduct"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Price the buyer's cart as placing the order would: subtotal, sale discounts, shipping estimate, tax and grand total, with warnings for lines short of stock, with a changed price or with a product no longer sold",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get Cart Summary",
+                "responses": {
+                    "200": {
+                        "description": "Cart summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.CartSummaryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to price cart",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserve stock for the selected products for a limited time so it can't be sold to another buyer before the order is placed. Starting a new checkout replaces the previous reservations. The response prices the order as GET /cart/summary does.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Orders"
+                ],
+                "summary": "Start Checkout",
+                "parameters": [
+                    {
+                        "description": "List of products and quantities",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.OrderItemRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock reserved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.CheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enough stock available",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Release the stock reserved by the buyer's current checkout",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Orders"
+                ],
+                "summary": "Cancel Checkout",
+                "responses": {
+                    "200": {
+                        "description": "Checkout cancelled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/cart": {
+            "get": {
+                "description": "Retrieve all items of a guest cart, priced at the price in effect now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get Guest Cart Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of cart items",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.CartItemResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Cart token missing",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve cart items",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the quantity of a specific item of a guest cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Update Guest Cart Item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Cart item details",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart item updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart item",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a product to the cart of a guest. Without a known cart token a new guest cart is created, the returned cart_token identifies it in later requests and is merged into the buyer's cart on sign in or registration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Add Product to Guest Cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Product ID and Quantity",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.AddToCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product added to cart successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete one or more items from a guest cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Delete Guest Cart Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Cart Item IDs to delete",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.DeleteCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart items deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete cart items",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/cart/summary": {
+            "get": {
+                "description": "Price a guest cart: subtotal, sale discounts, shipping estimate, tax and grand total, with warnings for lines short of stock, with a changed price or with a product no longer sold",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get Guest Cart Summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.CartSummaryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Cart token missing",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to price cart",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new order with selected products, validate stock, deduct quantities. Bundles deduct the stock of their components, which are recorded as items of the bundle. Stock reserved by the buyer's checkout is used, stock reserved by other buyers is not available. The total is the grand total of GET /cart/summary, shipping and tax included, and the order keeps its subtotal, discount, shipping and tax.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Orders"
+                ],
+                "summary": "Place Order",
+                "parameters": [
+                    {
+                        "description": "List of products and quantities",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.OrderItemRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order placed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "order_id": {
+                                                    "type": "integer"
+                                                },
+                                                "total_amount": {
+                                                    "type": "number"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Validation Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{order_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve details of a specific order, accessible only to the order's buyer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Orders"
+                ],
+                "summary": "Get Order Items Detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order details fetched successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.OrderDetailWithItemsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch order details",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of orders placed by the authenticated buyer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Orders"
+                ],
+                "summary": "View Orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of products with seller details",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.OrderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/by-slug/{slug}": {
+            "get": {
+                "description": "Retrieve the details of a product by its slug. A slug the product had before a rename redirects permanently to the current one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get Product Detail by Slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional bearer token, adds is_wishlisted to the response and records the view for buyers",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product details with seller information and gallery",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.ProductDetailWithSeller"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "301": {
+                        "description": "Redirect to the current slug"
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}": {
+            "get": {
+                "description": "Retrieve details of a specific product with seller information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get Product Detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional bearer token, adds is_wishlisted to the response and records the view for buyers",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product details with seller information and gallery",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.ProductDetailWithSeller"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/recommendations": {
+            "get": {
+                "description": "Products frequently bought together with this product in finished orders. When there isn't enough order data the list is filled with best-sellers of the same category.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get Product Recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations (default: 8, max: 24)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommended products",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.RecommendedProduct"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/reviews": {
+            "get": {
+                "description": "Retrieve a paginated list of visible reviews for a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get Product Reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: newest, helpful, rating_high or rating_low (default: newest)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of reviews",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.ReviewResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Post a 1-5 rating with optional text and photos. Only buyers with a finished order containing the product can review it, once per product.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Review a Product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rating from 1 to 5",
+                        "name": "rating",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review text",
+                        "name": "body",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Review photos (repeat the field, up to 5)",
+                        "name": "photos",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.ReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Product was not bought by this buyer",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Retrieve a paginated list of products with seller details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get Products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search specific product by keyword (default: ",
+                        "name": "search_product",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only products of this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products of this brand (case insensitive)",
+                        "name": "brand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products whose specification attribute ` + "`" + `key` + "`" + ` equals the value, e.g. attr[color]=red",
+                        "name": "attr[key]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of products with seller details",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.ProductWithSeller"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/recently-viewed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the products the buyer viewed most recently. Products that were archived or are out of stock are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get Recently Viewed Products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of products (default: 20, max: 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recently viewed products",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.RecentlyViewedResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the buyer's recently viewed history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Clear Recently Viewed Products",
+                "responses": {
+                    "200": {
+                        "description": "History cleared successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the profile of the currently authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Profile"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "Profile retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.ProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the profile details of the currently authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Profile"
+                ],
+                "summary": "Edit User Profile",
+                "parameters": [
+                    {
+                        "description": "Profile data to update",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.EditProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.EditProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Validation Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register a new user with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest cart token, the guest cart is merged into the new buyer's cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.SignInResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request: Invalid input/Email is already registered",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{review_id}/helpful": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Vote a review as helpful. Voting twice has no effect and buyers can't vote on their own reviews.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Mark Review Helpful",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vote recorded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "helpful_count": {
+                                                    "type": "integer"
+                                                },
+                                                "review_id": {
+                                                    "type": "integer"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw a helpful vote from a review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Remove Helpful Vote",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vote removed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "helpful_count": {
+                                                    "type": "integer"
+                                                },
+                                                "review_id": {
+                                                    "type": "integer"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signin": {
+            "post": {
+                "description": "Authenticates a user with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Auth"
+                ],
+                "summary": "Sign in a user (buyer)",
+                "parameters": [
+                    {
+                        "description": "User sign-in data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.SignInRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest cart token, the guest cart is merged into the buyer's cart",
+                        "name": "X-Cart-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Login successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/user.SignInResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request: Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemap.xml": {
+            "get": {
+                "description": "Sitemap of the active products and the categories. A large catalog gets a sitemap index pointing to the files under /sitemaps instead.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "SEO"
+                ],
+                "summary": "Sitemap",
+                "responses": {
+                    "200": {
+                        "description": "Sitemap or sitemap index",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps/{file}": {
+            "get": {
+                "description": "One file of a sitemap split by /sitemap.xml, such as products-2.xml or categories-1.xml",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "SEO"
+                ],
+                "summary": "Sitemap File",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File name, e.g. products-1.xml",
+                        "name": "file",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sitemap",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sitemap not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the buyer's wishlist with the current price and available stock of every product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Get Wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of wishlist items",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helper.PaginationResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/user.WishlistItemResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve wishlist",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save a product for later without putting it in the cart. Adding a product twice has no effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Add Product to Wishlist",
+                "parameters": [
+                    {
+                        "description": "Product ID",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.AddToWishlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product added to wishlist successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{product_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a product from the buyer's wishlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Remove Product from Wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product removed from wishlist successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not in wishlist",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{product_id}/move-to-cart": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a wishlist product to the cart and remove it from the wishlist in one call",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Move Wishlist Item to Cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity to put in the cart (default: 1)",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/user.MoveToCartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product moved to cart successfully",
+                        "schema": {
+                            "$ref": "#/definitions/helper.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not in wishlist",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helper.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.AddStockMovementRequest": {
+            "type": "object",
+            "required": [
+                "delta",
+                "reason"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "adjustment",
+                        "return"
+                    ],
+                    "example": "restock"
+                },
+                "reference_id": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "po:2024-118"
+                }
+            }
+        },
+        "admin.AdminReviewResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "buyer_id": {
+                    "type": "integer"
+                },
+                "buyer_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "hidden_at": {
+                    "type": "string"
+                },
+                "hidden_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_hidden": {
+                    "type": "boolean"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.BundleComponentRequest": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "admin.BundleComponentResponse": {
+            "type": "object",
+            "properties": {
+                "is_available": {
+                    "description": "False once the component is archived",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.Category": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.CategoryAttributeRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "label",
+                "type"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "volume"
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Volume"
+                },
+                "options": {
+                    "description": "Allowed values, required for enum attributes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "number",
+                        "boolean",
+                        "enum"
+                    ],
+                    "example": "number"
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "ml"
+                }
+            }
+        },
+        "admin.CategoryAttributeResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.CreateSalePriceRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "sale_price",
+                "starts_at"
+            ],
+            "properties": {
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-12-02T00:00:00+07:00"
+                },
+                "sale_price": {
+                    "type": "number",
+                    "example": 7.5
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-12-01T00:00:00+07:00"
+                }
+            }
+        },
+        "admin.CreateUploadRequest": {
+            "type": "object",
+            "required": [
+                "content_type",
+                "filename"
+            ],
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "filename": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "photo.jpg"
+                }
+            }
+        },
+        "admin.CreateUploadResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "headers": {
+                    "description": "Headers to send with the file",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "upload_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "upload_url": {
+                    "description": "Signed URL to PUT the file to",
+                    "type": "string"
+                }
+            }
+        },
+        "admin.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid input"
+                }
+            }
+        },
+        "admin.GetProductDetailResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.ProductAttributeResponse"
+                    }
+                },
+                "available_stock": {
+                    "description": "Stock minus reserved stock",
+                    "type": "integer"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "$ref": "#/definitions/admin.Category"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Products a bundle contains",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.BundleComponentResponse"
+                    }
+                },
+                "created_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "description_format": {
+                    "type": "string"
+                },
+                "description_html": {
+                    "type": "string"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "image_url": {
+                    "description": "URL or path to the image",
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.ProductImageResponse"
+                    }
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "low_stock_alerted_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "primary_image": {
+                    "description": "Renditions of a newly uploaded image",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin.ProductImageResponse"
+                        }
+                    ]
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "reorder_threshold": {
+                    "type": "integer"
+                },
+                "reserved_stock": {
+                    "description": "Held by active checkout reservations",
+                    "type": "integer"
+                },
+                "seller": {
+                    "$ref": "#/definitions/admin.Profile"
+                },
+                "seller_id": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Also sent as the ETag",
+                    "type": "integer"
+                },
+                "weight_grams": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
+        "admin.GetProductResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "description_format": {
+                    "type": "string"
+                },
+                "description_html": {
+                    "type": "string"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "image_url": {
+                    "description": "URL or path to the image",
+                    "type": "string"
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "low_stock_alerted_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "primary_image": {
+                    "description": "Renditions of a newly uploaded image",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin.ProductImageResponse"
+                        }
+                    ]
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "reorder_threshold": {
+                    "type": "integer"
+                },
+                "seller_id": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Also sent as the ETag",
+                    "type": "integer"
+                },
+                "weight_grams": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
+        "admin.GetProductResponseComplete": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "$ref": "#/definitions/admin.Category"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "description_format": {
+                    "type": "string"
+                },
+                "description_html": {
+                    "type": "string"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "image_url": {
+                    "description": "URL or path to the image",
+                    "type": "string"
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "low_stock_alerted_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "primary_image": {
+                    "description": "Renditions of a newly uploaded image",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin.ProductImageResponse"
+                        }
+                    ]
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "reorder_threshold": {
+                    "type": "integer"
+                },
+                "seller": {
+                    "$ref": "#/definitions/admin.Profile"
+                },
+                "seller_id": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Also sent as the ETag",
+                    "type": "integer"
+                },
+                "weight_grams": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
+        "admin.HideReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Contains personal information"
+                }
+            }
+        },
+        "admin.ImportDryRunResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.ImportRowError"
+                    }
+                },
+                "invalid_rows": {
+                    "type": "integer"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.ImportJobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_count": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.ImportRowError"
+                    }
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "description": "Percentage of processed rows",
+                    "type": "number",
+                    "example": 42.5
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "row": {
+                    "description": "Line in the file, the header being line 1",
+                    "type": "integer",
+                    "example": 3
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "MILK-1L"
+                }
+            }
+        },
+        "admin.LowStockProductResponse": {
+            "type": "object",
+            "properties": {
+                "low_stock_alerted_at": {
+                    "description": "When admins were alerted, null if not yet",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reorder_threshold": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "admin.OrderBuyerResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "buyer@example.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "buyer1234"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "admin.OrderDetailWithItemsResponse": {
+            "type": "object",
+            "properties": {
+                "buyer": {
+                    "$ref": "#/definitions/admin.OrderBuyerResponse"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.OrderItemResponse"
+                    }
+                },
+                "shipping": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "description": "How the total adds up, as stored when the order was placed",
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "bundle_item_id": {
+                    "description": "Set on the components of a bundle, which are free",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "image_url": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_name": {
                     "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "number"
                 }
             }
         },
-        "admin.ErrorResponse": {
+        "admin.OrphanedMediaResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Invalid input"
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deletable_at": {
+                    "description": "When garbage collection may delete the object",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "original_filename": {
+                    "type": "string"
+                },
+                "orphaned_at": {
+                    "description": "Null until garbage collection next runs",
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "admin.GetProductResponseComplete": {
+        "admin.PriceChangeResponse": {
             "type": "object",
             "properties": {
-                "category": {
-                    "$ref": "#/definitions/admin.Category"
+                "actor_email": {
+                    "type": "string"
                 },
-                "category_id": {
+                "actor_id": {
                     "type": "integer"
                 },
                 "created_at": {
-                    "description": "Changed to string",
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "number"
+                },
+                "old_price": {
+                    "type": "number"
+                },
+                "sale_price_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "regular, sale or sale_cancelled",
+                    "type": "string",
+                    "example": "regular"
+                }
+            }
+        },
+        "admin.ProductAttributeResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "volume"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Volume"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "ml"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "500"
+                }
+            }
+        },
+        "admin.ProductImageResponse": {
+            "type": "object",
+            "properties": {
+                "alt_text": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "medium_url": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Large rendition",
+                    "type": "string"
+                }
+            }
+        },
+        "admin.ProductRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "baseline, created, updated, imported, archived, unarchived or rolled_back",
+                    "type": "string",
+                    "example": "updated"
+                },
+                "actor_email": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Compared to the previous revision",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.RevisionChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "restored_from": {
+                    "description": "Revision a rollback went back to",
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.ProductSnapshot"
+                }
+            }
+        },
+        "admin.Profile": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
                 "image_url": {
-                    "description": "URL or path to the image",
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                }
+            }
+        },
+        "admin.ReorderProductImagesRequest": {
+            "type": "object",
+            "required": [
+                "image_ids"
+            ],
+            "properties": {
+                "image_ids": {
+                    "description": "Image IDs in their new display order",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "primary_image_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.RevisionChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Attributes appear as attributes.\u003ckey\u003e",
+                    "type": "string",
+                    "example": "price"
+                },
+                "new": {},
+                "old": {
+                    "description": "Null when the field was not set before"
+                }
+            }
+        },
+        "admin.SalePriceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "created_by": {
+                    "type": "integer"
                 },
-                "seller": {
-                    "$ref": "#/definitions/admin.Profile"
+                "ends_at": {
+                    "type": "string"
                 },
-                "seller_id": {
+                "id": {
                     "type": "integer"
                 },
-                "stock": {
+                "product_id": {
                     "type": "integer"
                 },
-                "updated_at": {
-                    "description": "Changed to string",
+                "sale_price": {
+                    "type": "number"
+                },
+                "starts_at": {
                     "type": "string"
+                },
+                "state": {
+                    "description": "scheduled, active or ended",
+                    "type": "string",
+                    "example": "scheduled"
+                }
+            }
+        },
+        "admin.SetBundleComponentsRequest": {
+            "type": "object",
+            "required": [
+                "components"
+            ],
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/admin.BundleComponentRequest"
+                    }
+                }
+            }
+        },
+        "admin.SignInRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "password123"
                 }
             }
         },
-        "admin.OrderBuyerResponse": {
+        "admin.SignInResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "buyer@example.com"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "name": {
-                    "type": "string",
-                    "example": "buyer1234"
-                },
-                "phone": {
+                "token": {
                     "type": "string",
-                    "example": "123456789"
+                    "example": "your_jwt_token"
                 }
             }
         },
-        "admin.OrderDetailWithItemsResponse": {
+        "admin.StockMismatchResponse": {
             "type": "object",
             "properties": {
-                "buyer": {
-                    "$ref": "#/definitions/admin.OrderBuyerResponse"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "order_id": {
+                "difference": {
+                    "description": "Stock minus ledger stock",
                     "type": "integer"
                 },
-                "order_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/admin.OrderItemResponse"
-                    }
+                "ledger_stock": {
+                    "description": "Sum of the product's movements",
+                    "type": "integer"
                 },
-                "status": {
+                "name": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
+                "product_id": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "sku": {
                     "type": "string"
+                },
+                "stock": {
+                    "description": "Stock on the product",
+                    "type": "integer"
                 }
             }
         },
-        "admin.OrderItemResponse": {
+        "admin.StockMovementResponse": {
             "type": "object",
             "properties": {
-                "image_url": {
+                "actor_email": {
                     "type": "string"
                 },
-                "order_id": {
+                "actor_id": {
                     "type": "integer"
                 },
-                "price": {
-                    "type": "number"
+                "balance_after": {
+                    "type": "integer",
+                    "example": 18
                 },
-                "product_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "quantity": {
+                "delta": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "id": {
                     "type": "integer"
                 },
-                "total_price": {
-                    "type": "number"
+                "note": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "sale"
+                },
+                "reference_id": {
+                    "type": "string",
+                    "example": "order:42"
                 }
             }
         },
-        "admin.Profile": {
+        "admin.StockReconciliationResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "checked_products": {
                     "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.StockMismatchResponse"
+                    }
                 }
             }
         },
-        "admin.SignInRequest": {
+        "admin.UpdateCategoryAttributesRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.CategoryAttributeRequest"
+                    }
+                }
+            }
+        },
+        "admin.UpdateProductImageRequest": {
+            "type": "object",
+            "properties": {
+                "alt_text": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "maxLength": 255
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6,
-                    "example": "password123"
+                "is_primary": {
+                    "type": "boolean"
                 }
             }
         },
-        "admin.SignInResponse": {
+        "admin.UploadResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "medium_url": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string",
-                    "example": "your_jwt_token"
+                    "example": "confirmed"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "upload_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "url": {
+                    "description": "Large rendition, once confirmed",
+                    "type": "string"
                 }
             }
         },
@@ -1691,6 +6413,59 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ProductSnapshot": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "description_format": {
+                    "type": "string"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "reorder_threshold": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "weight_grams": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
         "user.AddToCartRequest": {
             "type": "object",
             "required": [
@@ -1707,9 +6482,45 @@ const docTemplate = `{
                 }
             }
         },
+        "user.AddToWishlistRequest": {
+            "type": "object",
+            "required": [
+                "product_id"
+            ],
+            "properties": {
+                "product_id": {
+                    "description": "ID of the product",
+                    "type": "integer"
+                }
+            }
+        },
+        "user.BundleItemResponse": {
+            "type": "object",
+            "properties": {
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
         "user.CartItemResponse": {
             "type": "object",
             "properties": {
+                "compare_at_price": {
+                    "description": "Regular price while a sale is running",
+                    "type": "number"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1733,6 +6544,111 @@ const docTemplate = `{
                 }
             }
         },
+        "user.CartSummaryItem": {
+            "type": "object",
+            "properties": {
+                "cart_item_id": {
+                    "type": "integer"
+                },
+                "compare_at_price": {
+                    "description": "Regular price while a sale is running",
+                    "type": "number"
+                },
+                "discount": {
+                    "description": "Taken off by a running sale",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "Per unit, a running sale included",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "description": "At the regular price",
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.CartWarning"
+                    }
+                }
+            }
+        },
+        "user.CartSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "grand_total": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.CartSummaryItem"
+                    }
+                },
+                "shipping": {
+                    "description": "Estimate, by weight unless the order ships free",
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                }
+            }
+        },
+        "user.CartWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "insufficient_stock, price_changed, product_archived or product_unavailable",
+                    "type": "string",
+                    "example": "insufficient_stock"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.CheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Reserved stock is released after this time",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.ReservationResponse"
+                    }
+                },
+                "summary": {
+                    "description": "What placing the order costs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.CartSummaryResponse"
+                        }
+                    ]
+                }
+            }
+        },
         "user.CreateUserRequest": {
             "type": "object",
             "required": [
@@ -1804,6 +6720,16 @@ const docTemplate = `{
                 }
             }
         },
+        "user.MoveToCartRequest": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "description": "Defaults to 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "user.OrderDetailWithItemsResponse": {
             "type": "object",
             "properties": {
@@ -1813,6 +6739,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "discount": {
+                    "type": "number"
+                },
                 "order_id": {
                     "type": "integer"
                 },
@@ -1822,9 +6751,18 @@ const docTemplate = `{
                         "$ref": "#/definitions/user.OrderItemDetailResponse"
                     }
                 },
+                "shipping": {
+                    "type": "number"
+                },
                 "status": {
                     "type": "string"
                 },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
                 "total_amount": {
                     "type": "number"
                 },
@@ -1836,6 +6774,13 @@ const docTemplate = `{
         "user.OrderItemDetailResponse": {
             "type": "object",
             "properties": {
+                "bundle_item_id": {
+                    "description": "Set on the components of a bundle, which are free",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "image_url": {
                     "type": "string"
                 },
@@ -1893,9 +6838,153 @@ const docTemplate = `{
                 }
             }
         },
+        "user.ProductDetailWithSeller": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "bundle_items": {
+                    "description": "What a bundle contains",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.BundleItemResponse"
+                    }
+                },
+                "compare_at_price": {
+                    "description": "Regular price to strike through during a sale, null otherwise",
+                    "type": "number"
+                },
+                "description": {
+                    "description": "Sanitized HTML",
+                    "type": "string"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.ProductImageResponse"
+                    }
+                },
+                "is_wishlisted": {
+                    "description": "Only set for signed in callers",
+                    "type": "boolean"
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Sale price while a sale is running",
+                    "type": "number"
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "seller_id": {
+                    "type": "integer"
+                },
+                "seller_name": {
+                    "description": "Omitempty for null values",
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "specifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.ProductSpecification"
+                    }
+                },
+                "stock": {
+                    "description": "On-hand stock minus active checkout reservations",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "simple or bundle",
+                    "type": "string",
+                    "example": "simple"
+                },
+                "weight_grams": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
+        "user.ProductImageResponse": {
+            "type": "object",
+            "properties": {
+                "alt_text": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "medium_url": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Large rendition",
+                    "type": "string"
+                }
+            }
+        },
+        "user.ProductSpecification": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "volume"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Volume"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "ml"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "500"
+                }
+            }
+        },
         "user.ProductWithSeller": {
             "type": "object",
             "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "compare_at_price": {
+                    "description": "Regular price to strike through during a sale, null otherwise",
+                    "type": "number"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1906,8 +6995,15 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "price": {
+                    "description": "Sale price while a sale is running",
                     "type": "number"
                 },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
                 "seller_id": {
                     "type": "integer"
                 },
@@ -1915,8 +7011,17 @@ const docTemplate = `{
                     "description": "Omitempty for null values",
                     "type": "string"
                 },
+                "slug": {
+                    "type": "string"
+                },
                 "stock": {
+                    "description": "On-hand stock minus active checkout reservations",
                     "type": "integer"
+                },
+                "type": {
+                    "description": "simple or bundle",
+                    "type": "string",
+                    "example": "simple"
                 }
             }
         },
@@ -1949,6 +7054,130 @@ const docTemplate = `{
                 }
             }
         },
+        "user.RecentlyViewedResponse": {
+            "type": "object",
+            "properties": {
+                "compare_at_price": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "viewed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.RecommendedProduct": {
+            "type": "object",
+            "properties": {
+                "compare_at_price": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "frequently_bought_together or best_seller",
+                    "type": "string",
+                    "example": "frequently_bought_together"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "user.ReservationResponse": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "user.ReviewPhotoResponse": {
+            "type": "object",
+            "properties": {
+                "medium_url": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Large rendition",
+                    "type": "string"
+                }
+            }
+        },
+        "user.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "buyer_id": {
+                    "type": "integer"
+                },
+                "buyer_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.ReviewPhotoResponse"
+                    }
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "user.SignInRequest": {
             "type": "object",
             "required": [
@@ -2012,6 +7241,39 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "user.WishlistItemResponse": {
+            "type": "object",
+            "properties": {
+                "compare_at_price": {
+                    "description": "Regular price while a sale is running",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "in_stock": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,19 @@
+package admin
+
+type BundleComponentRequest struct {
+	ProductID uint64 `json:"product_id" binding:"required" example:"2"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0" example:"1"`
+}
+
+type SetBundleComponentsRequest struct {
+	Components []BundleComponentRequest `json:"components" binding:"required,min=1,dive"`
+}
+
+type BundleComponentResponse struct {
+	ProductID   uint64 `json:"product_id"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	Stock       int    `json:"stock"`
+	IsAvailable bool   `json:"is_available"` // False once the component is archived
+}
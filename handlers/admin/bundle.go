@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// @Summary List the components of a bundle
+// @Description Admin lists the products a bundle contains, with the quantity per bundle and their stock
+// @Tags Admin Product
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} helper.SuccessResponse{data=[]BundleComponentResponse} "Bundle components retrieved successfully"
+// @Failure 404 {object} helper.ErrorResponse "Bundle not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/components [get]
+func GetBundleComponents(c *gin.Context) {
+	bundleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var bundle models.Product
+	err = config.DB.Unscoped().Select("id", "type").First(&bundle, bundleID).Error
+	if err != nil || bundle.Type != models.ProductTypeBundle {
+		helper.SendError(c, http.StatusNotFound, []string{"Bundle not found"})
+		return
+	}
+
+	components, err := _getBundleComponents(config.DB, bundleID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve bundle components"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Bundle components retrieved successfully", components)
+}
+
+// @Summary Set the components of a bundle
+// @Description Admin replaces the products a bundle contains. Components must be existing products that aren't bundles themselves.
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body SetBundleComponentsRequest true "Components and quantities per bundle"
+// @Success 200 {object} helper.SuccessResponse{data=[]BundleComponentResponse} "Bundle components updated successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 404 {object} helper.ErrorResponse "Bundle not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/components [put]
+func SetBundleComponents(c *gin.Context) {
+	bundleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var req SetBundleComponentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input", err.Error()})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var bundle models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bundle, bundleID).Error; err != nil {
+			return err
+		}
+		if bundle.Type != models.ProductTypeBundle {
+			return gorm.ErrRecordNotFound
+		}
+		rows, err := _validateBundleComponents(tx, bundleID, req.Components)
+		if err != nil {
+			return err
+		}
+		return _replaceBundleComponents(tx, bundleID, rows)
+	})
+	var vErr *validationError
+	switch {
+	case errors.As(err, &vErr):
+		helper.SendError(c, http.StatusBadRequest, vErr.messages)
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Bundle not found"})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update bundle components"})
+		return
+	}
+
+	components, err := _getBundleComponents(config.DB, bundleID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve bundle components"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Bundle components updated successfully", components)
+}
+
+func _getBundleComponents(db *gorm.DB, bundleID uint64) ([]BundleComponentResponse, error) {
+	components := []BundleComponentResponse{}
+	err := db.Table("bundle_components").
+		Select(`
+			products.id AS product_id,
+			products.sku,
+			products.name,
+			bundle_components.quantity,
+			products.stock,
+			products.deleted_at IS NULL AS is_available`).
+		Joins("JOIN products ON products.id = bundle_components.component_id").
+		Where("bundle_components.bundle_id = ?", bundleID).
+		Order("products.name ASC").
+		Scan(&components).Error
+	return components, err
+}
+
+// _parseBundleComponents reads the components form field, a JSON array of
+// product_id and quantity pairs
+func _parseBundleComponents(raw string) ([]BundleComponentRequest, error) {
+	var components []BundleComponentRequest
+	if err := json.Unmarshal([]byte(raw), &components); err != nil {
+		return nil, errors.New(`components must be a JSON array, e.g. [{"product_id": 2, "quantity": 1}]`)
+	}
+	return components, nil
+}
+
+// _validateBundleComponents checks components against the catalog and returns
+// them as rows of the bundle. Pass 0 for a bundle that doesn't exist yet.
+func _validateBundleComponents(db *gorm.DB, bundleID uint64, components []BundleComponentRequest) ([]models.BundleComponent, error) {
+	if len(components) == 0 {
+		return nil, &validationError{messages: []string{"A bundle needs at least one component"}}
+	}
+
+	var messages []string
+	seen := make(map[uint64]bool, len(components))
+	rows := make([]models.BundleComponent, 0, len(components))
+	for _, component := range components {
+		switch {
+		case component.ProductID == bundleID:
+			messages = append(messages, "A bundle can't contain itself")
+			continue
+		case seen[component.ProductID]:
+			messages = append(messages, fmt.Sprintf("Product %d is listed more than once", component.ProductID))
+			continue
+		case component.Quantity < 1:
+			messages = append(messages, fmt.Sprintf("Quantity of product %d must be at least 1", component.ProductID))
+			continue
+		}
+		seen[component.ProductID] = true
+
+		var product models.Product
+		err := db.Select("id", "type").First(&product, component.ProductID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			messages = append(messages, fmt.Sprintf("Product %d not found", component.ProductID))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if product.Type == models.ProductTypeBundle {
+			messages = append(messages, fmt.Sprintf("Product %d is a bundle, bundles can't be nested", component.ProductID))
+			continue
+		}
+
+		rows = append(rows, models.BundleComponent{
+			BundleID:    bundleID,
+			ComponentID: component.ProductID,
+			Quantity:    component.Quantity,
+		})
+	}
+	if len(messages) > 0 {
+		return nil, &validationError{messages: messages}
+	}
+	return rows, nil
+}
+
+// _replaceBundleComponents makes rows the only components of the bundle
+func _replaceBundleComponents(tx *gorm.DB, bundleID uint64, rows []models.BundleComponent) error {
+	if err := tx.Where("bundle_id = ?", bundleID).Delete(&models.BundleComponent{}).Error; err != nil {
+		return err
+	}
+	for i := range rows {
+		rows[i].BundleID = bundleID
+	}
+	return tx.Create(&rows).Error
+}
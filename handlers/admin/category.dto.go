@@ -0,0 +1,32 @@
+package admin
+
+type CategoryAttributeRequest struct {
+	Key      string   `json:"key" binding:"required,max=100" example:"volume"`
+	Label    string   `json:"label" binding:"required,max=255" example:"Volume"`
+	Type     string   `json:"type" binding:"required,oneof=text number boolean enum" example:"number"`
+	Options  []string `json:"options,omitempty"` // Allowed values, required for enum attributes
+	Unit     string   `json:"unit,omitempty" binding:"max=32" example:"ml"`
+	Required bool     `json:"required"`
+}
+
+type UpdateCategoryAttributesRequest struct {
+	Attributes []CategoryAttributeRequest `json:"attributes" binding:"dive"`
+}
+
+type CategoryAttributeResponse struct {
+	ID       uint     `json:"id"`
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+	Unit     string   `json:"unit"`
+	Required bool     `json:"required"`
+	Position int      `json:"position"`
+}
+
+type ProductAttributeResponse struct {
+	Key   string `json:"key" example:"volume"`
+	Label string `json:"label" example:"Volume"`
+	Value string `json:"value" example:"500"`
+	Unit  string `json:"unit" example:"ml"`
+}
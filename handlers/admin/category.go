@@ -0,0 +1,327 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Get category attribute schema
+// @Description Retrieve the specification attributes products of a category can define
+// @Tags Admin Category
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} helper.SuccessResponse{data=[]CategoryAttributeResponse} "Attribute schema"
+// @Failure 400 {object} helper.ErrorResponse "Invalid category ID"
+// @Failure 404 {object} helper.ErrorResponse "Category not found"
+// @Router /admin/category/{id}/attributes [get]
+func GetCategoryAttributes(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid category ID"})
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, categoryID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Category not found"})
+		return
+	}
+
+	attributes, err := _getCategoryAttributes(config.DB, uint(categoryID))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch category attributes"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Category attributes retrieved successfully", _mapCategoryAttributes(attributes))
+}
+
+// @Summary Replace category attribute schema
+// @Description Admin replaces the specification attributes of a category. Values stored on products for removed attributes are deleted.
+// @Tags Admin Category
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param payload body UpdateCategoryAttributesRequest true "Attribute schema in display order"
+// @Success 200 {object} helper.SuccessResponse{data=[]CategoryAttributeResponse} "Attribute schema"
+// @Failure 400 {object} helper.ErrorResponse "Validation Error"
+// @Failure 404 {object} helper.ErrorResponse "Category not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/category/{id}/attributes [put]
+func UpdateCategoryAttributes(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid category ID"})
+		return
+	}
+
+	var req UpdateCategoryAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, categoryID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Category not found"})
+		return
+	}
+
+	// Validate the whole schema before touching the database
+	var messages []string
+	seen := make(map[string]bool)
+	for _, attr := range req.Attributes {
+		if !attributeKeyPattern.MatchString(attr.Key) {
+			messages = append(messages, fmt.Sprintf("invalid attribute key %q: use lowercase letters, digits and underscores", attr.Key))
+		}
+		if seen[attr.Key] {
+			messages = append(messages, fmt.Sprintf("duplicate attribute key %q", attr.Key))
+		}
+		seen[attr.Key] = true
+		if attr.Type == models.AttributeTypeEnum && len(attr.Options) == 0 {
+			messages = append(messages, fmt.Sprintf("enum attribute %q needs at least one option", attr.Key))
+		}
+	}
+	if len(messages) > 0 {
+		helper.SendError(c, http.StatusBadRequest, messages)
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		keys := make([]string, 0, len(req.Attributes))
+		for _, attr := range req.Attributes {
+			keys = append(keys, attr.Key)
+		}
+
+		// Drop product values of attributes that are no longer in the schema
+		removed := tx.Where("product_id IN (SELECT id FROM products WHERE category_id = ?)", categoryID)
+		if len(keys) > 0 {
+			removed = removed.Where("key NOT IN ?", keys)
+		}
+		if err := removed.Delete(&models.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", categoryID).Delete(&models.CategoryAttribute{}).Error; err != nil {
+			return err
+		}
+
+		for position, attr := range req.Attributes {
+			options := make([]string, 0, len(attr.Options))
+			for _, option := range attr.Options {
+				if option = strings.TrimSpace(option); option != "" {
+					options = append(options, option)
+				}
+			}
+			if err := tx.Create(&models.CategoryAttribute{
+				CategoryID: uint(categoryID),
+				Key:        attr.Key,
+				Label:      attr.Label,
+				Type:       attr.Type,
+				Options:    strings.Join(options, ","),
+				Unit:       attr.Unit,
+				Required:   attr.Required,
+				Position:   position,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update category attributes"})
+		return
+	}
+
+	attributes, err := _getCategoryAttributes(config.DB, uint(categoryID))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch category attributes"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Category attributes updated successfully", _mapCategoryAttributes(attributes))
+}
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// validationError carries user-facing messages that should be answered with 400
+type validationError struct {
+	messages []string
+}
+
+func (e *validationError) Error() string {
+	return strings.Join(e.messages, "; ")
+}
+
+func _getCategoryAttributes(db *gorm.DB, categoryID uint) ([]models.CategoryAttribute, error) {
+	var attributes []models.CategoryAttribute
+	err := db.Where("category_id = ?", categoryID).
+		Order("position ASC, id ASC").
+		Find(&attributes).Error
+	return attributes, err
+}
+
+func _mapCategoryAttributes(attributes []models.CategoryAttribute) []CategoryAttributeResponse {
+	response := make([]CategoryAttributeResponse, len(attributes))
+	for i, attr := range attributes {
+		options := []string{}
+		if attr.Options != "" {
+			options = strings.Split(attr.Options, ",")
+		}
+		response[i] = CategoryAttributeResponse{
+			ID:       attr.ID,
+			Key:      attr.Key,
+			Label:    attr.Label,
+			Type:     attr.Type,
+			Options:  options,
+			Unit:     attr.Unit,
+			Required: attr.Required,
+			Position: attr.Position,
+		}
+	}
+	return response
+}
+
+// _parseAttributes decodes the `attributes` form field, a JSON object of
+// specification key to scalar value, e.g. {"volume": 500, "color": "red"}
+func _parseAttributes(raw string) (map[string]string, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil, errors.New("attributes must be a JSON object")
+	}
+
+	attrs := make(map[string]string, len(decoded))
+	for key, value := range decoded {
+		switch v := value.(type) {
+		case string:
+			attrs[key] = v
+		case float64:
+			attrs[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			attrs[key] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("attribute %q must be a string, number or boolean", key)
+		}
+	}
+	return attrs, nil
+}
+
+// _validateAttributes checks attribute values against the schema of the
+// category and returns them normalized. Products without a category can't
+// carry attributes.
+func _validateAttributes(db *gorm.DB, categoryID *uint, attrs map[string]string) (map[string]string, error) {
+	var schema []models.CategoryAttribute
+	if categoryID != nil {
+		var err error
+		if schema, err = _getCategoryAttributes(db, *categoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	byKey := make(map[string]models.CategoryAttribute, len(schema))
+	for _, attr := range schema {
+		byKey[attr.Key] = attr
+	}
+
+	var messages []string
+	normalized := make(map[string]string, len(attrs))
+	for key, value := range attrs {
+		value = strings.TrimSpace(value)
+		attr, ok := byKey[key]
+		if !ok {
+			messages = append(messages, fmt.Sprintf("unknown attribute %q for this category", key))
+			continue
+		}
+		if value == "" {
+			continue
+		}
+
+		switch attr.Type {
+		case models.AttributeTypeNumber:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				messages = append(messages, fmt.Sprintf("attribute %q must be a number", key))
+				continue
+			}
+			value = strconv.FormatFloat(n, 'f', -1, 64)
+		case models.AttributeTypeBoolean:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				messages = append(messages, fmt.Sprintf("attribute %q must be true or false", key))
+				continue
+			}
+			value = strconv.FormatBool(b)
+		case models.AttributeTypeEnum:
+			allowed := false
+			for _, option := range strings.Split(attr.Options, ",") {
+				if option == value {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				messages = append(messages, fmt.Sprintf("attribute %q must be one of: %s", key, strings.ReplaceAll(attr.Options, ",", ", ")))
+				continue
+			}
+		}
+		normalized[key] = value
+	}
+
+	for _, attr := range schema {
+		if _, ok := normalized[attr.Key]; attr.Required && !ok {
+			messages = append(messages, fmt.Sprintf("attribute %q is required", attr.Key))
+		}
+	}
+
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return nil, &validationError{messages: messages}
+	}
+	return normalized, nil
+}
+
+// _replaceProductAttributes stores attrs as the complete attribute set of a product
+func _replaceProductAttributes(tx *gorm.DB, productID uint64, attrs map[string]string) error {
+	if err := tx.Where("product_id = ?", productID).Delete(&models.ProductAttribute{}).Error; err != nil {
+		return err
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	rows := make([]models.ProductAttribute, 0, len(attrs))
+	for key, value := range attrs {
+		rows = append(rows, models.ProductAttribute{ProductID: productID, Key: key, Value: value})
+	}
+	return tx.Create(&rows).Error
+}
+
+func _getProductAttributes(db *gorm.DB, productID uint64) ([]ProductAttributeResponse, error) {
+	attributes := []ProductAttributeResponse{}
+	err := db.Table("product_attributes").
+		Select(`
+			product_attributes.key,
+			product_attributes.value,
+			COALESCE(category_attributes.label, product_attributes.key) AS label,
+			COALESCE(category_attributes.unit, '') AS unit`).
+		Joins("JOIN products ON products.id = product_attributes.product_id").
+		Joins("LEFT JOIN category_attributes ON category_attributes.category_id = products.category_id AND category_attributes.key = product_attributes.key").
+		Where("product_attributes.product_id = ?", productID).
+		Order("category_attributes.position ASC, product_attributes.key ASC").
+		Scan(&attributes).Error
+	return attributes, err
+}
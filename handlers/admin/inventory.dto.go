@@ -0,0 +1,45 @@
+package admin
+
+type StockMovementResponse struct {
+	ID           uint64  `json:"id"`
+	ProductID    uint64  `json:"product_id"`
+	Delta        int     `json:"delta" example:"-2"`
+	Reason       string  `json:"reason" example:"sale"`
+	ReferenceID  string  `json:"reference_id" example:"order:42"`
+	ActorID      *uint64 `json:"actor_id"`
+	ActorEmail   string  `json:"actor_email"`
+	Note         string  `json:"note"`
+	BalanceAfter int     `json:"balance_after" example:"18"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type AddStockMovementRequest struct {
+	Delta       int    `json:"delta" binding:"required,ne=0" example:"24"`
+	Reason      string `json:"reason" binding:"required,oneof=restock adjustment return" example:"restock"`
+	ReferenceID string `json:"reference_id" binding:"max=64" example:"po:2024-118"`
+	Note        string `json:"note" binding:"max=255"`
+}
+
+type StockMismatchResponse struct {
+	ProductID   uint64 `json:"product_id"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Stock       int    `json:"stock"`        // Stock on the product
+	LedgerStock int    `json:"ledger_stock"` // Sum of the product's movements
+	Difference  int    `json:"difference"`   // Stock minus ledger stock
+}
+
+type StockReconciliationResponse struct {
+	CheckedProducts int                     `json:"checked_products"`
+	Mismatches      []StockMismatchResponse `json:"mismatches"`
+}
+
+type LowStockProductResponse struct {
+	ProductID         uint64  `json:"product_id"`
+	SKU               string  `json:"sku"`
+	Name              string  `json:"name"`
+	Status            string  `json:"status"`
+	Stock             int     `json:"stock" example:"3"`
+	ReorderThreshold  int     `json:"reorder_threshold" example:"10"`
+	LowStockAlertedAt *string `json:"low_stock_alerted_at"` // When admins were alerted, null if not yet
+}
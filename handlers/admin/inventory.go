@@ -0,0 +1,266 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// @Summary Get stock history
+// @Description Admin lists the inventory movements of a product, newest first. Archived products are included.
+// @Tags Admin Inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Param reason query string false "Only movements with this reason (sale, restock, adjustment, return or cancellation)"
+// @Success 200 {object} helper.PaginationResponse{data=[]StockMovementResponse} "List of stock movements"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/stock-history [get]
+func GetStockHistory(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	var product models.Product
+	if err := config.DB.Unscoped().Select("id").First(&product, productID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	query := config.DB.Table("stock_movements").Where("stock_movements.product_id = ?", productID)
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("stock_movements.reason = ?", reason)
+	}
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count stock movements"})
+		return
+	}
+
+	var movements []StockMovementResponse
+	err = query.
+		Select(`
+			stock_movements.id,
+			stock_movements.product_id,
+			stock_movements.delta,
+			stock_movements.reason,
+			stock_movements.reference_id,
+			stock_movements.actor_id,
+			COALESCE(users.email, '') AS actor_email,
+			stock_movements.note,
+			stock_movements.balance_after,
+			stock_movements.created_at`).
+		Joins("LEFT JOIN users ON users.id = stock_movements.actor_id").
+		Order("stock_movements.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&movements).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve stock history"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Stock history retrieved successfully", movements, pagination)
+}
+
+// @Summary Record a stock movement
+// @Description Admin records a restock, a manual adjustment or a customer return. The product stock changes by the delta.
+// @Tags Admin Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body AddStockMovementRequest true "Stock movement"
+// @Success 201 {object} helper.SuccessResponse{data=StockMovementResponse} "Stock movement recorded successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 409 {object} helper.ErrorResponse "Stock would go below zero"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/stock-movements [post]
+func AddStockMovement(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var req AddStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input", err.Error()})
+		return
+	}
+
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID := uint64(claims["userid"].(float64))
+
+	movement := models.StockMovement{
+		ProductID:   productID,
+		Delta:       req.Delta,
+		Reason:      req.Reason,
+		ReferenceID: req.ReferenceID,
+		ActorID:     &adminID,
+		Note:        req.Note,
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return models.ApplyStockMovement(tx, &movement)
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	case errors.Is(err, models.ErrInsufficientStock):
+		helper.SendError(c, http.StatusConflict, []string{"Stock would go below zero"})
+		return
+	case errors.Is(err, models.ErrBundleStock):
+		helper.SendError(c, http.StatusBadRequest, []string{"Bundles take their stock from their components"})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to record stock movement"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Stock movement recorded successfully", StockMovementResponse{
+		ID:           movement.ID,
+		ProductID:    movement.ProductID,
+		Delta:        movement.Delta,
+		Reason:       movement.Reason,
+		ReferenceID:  movement.ReferenceID,
+		ActorID:      movement.ActorID,
+		Note:         movement.Note,
+		BalanceAfter: movement.BalanceAfter,
+		CreatedAt:    movement.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// @Summary Reconcile stock
+// @Description Admin checks every product, archived ones included, and lists those whose stock differs from the sum of their movements
+// @Tags Admin Inventory
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} helper.SuccessResponse{data=StockReconciliationResponse} "Reconciliation report"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/inventory/reconciliation [get]
+func GetStockReconciliation(c *gin.Context) {
+	var checked int64
+	if err := config.DB.Unscoped().Model(&models.Product{}).Count(&checked).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to reconcile stock"})
+		return
+	}
+
+	mismatches := []StockMismatchResponse{}
+	err := config.DB.Raw(`
+		SELECT
+			products.id AS product_id,
+			products.sku,
+			products.name,
+			COALESCE(products.stock, 0) AS stock,
+			COALESCE(ledger.total, 0) AS ledger_stock,
+			COALESCE(products.stock, 0) - COALESCE(ledger.total, 0) AS difference
+		FROM products
+		LEFT JOIN (
+			SELECT product_id, SUM(delta) AS total FROM stock_movements GROUP BY product_id
+		) ledger ON ledger.product_id = products.id
+		WHERE COALESCE(products.stock, 0) <> COALESCE(ledger.total, 0)
+		ORDER BY products.id ASC`).
+		Scan(&mismatches).Error
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to reconcile stock"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Stock reconciled successfully", StockReconciliationResponse{
+		CheckedProducts: int(checked),
+		Mismatches:      mismatches,
+	})
+}
+
+// @Summary Get low-stock products
+// @Description Admin lists products, archived ones excluded, whose stock is at or below their reorder threshold, emptiest first
+// @Tags Admin Inventory
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]LowStockProductResponse} "List of low-stock products"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/inventory/low-stock [get]
+func GetLowStockProducts(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.Product{}).
+		Where("reorder_threshold > 0 AND stock <= reorder_threshold")
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count low-stock products"})
+		return
+	}
+
+	var products []LowStockProductResponse
+	err = query.
+		Select("id AS product_id, sku, name, status, stock, reorder_threshold, low_stock_alerted_at").
+		Order("stock - reorder_threshold ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&products).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve low-stock products"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Low-stock products retrieved successfully", products, pagination)
+}
@@ -0,0 +1,13 @@
+package admin
+
+type OrphanedMediaResponse struct {
+	ID               uint64  `json:"id"`
+	Key              string  `json:"key"`
+	URL              string  `json:"url"`
+	ContentType      string  `json:"content_type"`
+	Size             int64   `json:"size"`
+	OriginalFilename string  `json:"original_filename"`
+	CreatedAt        string  `json:"created_at"`
+	OrphanedAt       *string `json:"orphaned_at"`  // Null until garbage collection next runs
+	DeletableAt      *string `json:"deletable_at"` // When garbage collection may delete the object
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"deketna/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Get orphaned media
+// @Description Admin lists stored media no product image or review photo references any more, oldest orphan first. It is a dry-run report of what media garbage collection deletes once the grace period has passed.
+// @Tags Admin Media
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]OrphanedMediaResponse} "List of orphaned media"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/media/orphans [get]
+func GetOrphanedMedia(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&models.MediaObject{}).Scopes(models.UnreferencedMedia)
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count orphaned media"})
+		return
+	}
+
+	var objects []models.MediaObject
+	err = query.
+		Order("orphaned_at ASC NULLS LAST, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&objects).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve orphaned media"})
+		return
+	}
+
+	grace := config.MediaGCGrace()
+	response := make([]OrphanedMediaResponse, len(objects))
+	for i, object := range objects {
+		response[i] = OrphanedMediaResponse{
+			ID:               object.ID,
+			Key:              object.Key,
+			URL:              storage.PublicURL(object.Key),
+			ContentType:      object.ContentType,
+			Size:             object.Size,
+			OriginalFilename: object.OriginalFilename,
+			CreatedAt:        object.CreatedAt.Format(time.RFC3339),
+		}
+		if object.OrphanedAt != nil {
+			orphanedAt := object.OrphanedAt.Format(time.RFC3339)
+			deletableAt := object.OrphanedAt.Add(grace).Format(time.RFC3339)
+			response[i].OrphanedAt = &orphanedAt
+			response[i].DeletableAt = &deletableAt
+		}
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Orphaned media retrieved successfully", response, pagination)
+}
@@ -15,6 +15,9 @@ type OrderItemResponse struct {
 	OrderID     uint64  `json:"order_id"`
 	TotalPrice  float64 `json:"total_price"`
 	ImageURL    string  `json:"image_url"`
+
+	ID           uint64  `json:"id"`
+	BundleItemID *uint64 `json:"bundle_item_id,omitempty"` // Set on the components of a bundle, which are free
 }
 
 type OrderResponse struct {
@@ -29,4 +32,10 @@ type OrderDetailWithItemsResponse struct {
 	OrderResponse
 	Buyer OrderBuyerResponse  `json:"buyer"`
 	Items []OrderItemResponse `json:"order_items"`
+
+	// How the total adds up, as stored when the order was placed
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount"`
+	Shipping float64 `json:"shipping"`
+	Tax      float64 `json:"tax"`
 }
@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +12,9 @@ import (
 	"deketna/models"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ViewOrders retrieves the orders for the authenticated buyer
@@ -93,6 +98,8 @@ func ViewOrders(c *gin.Context) {
 	if len(orderIDs) > 0 {
 		err = config.DB.Table("order_items").
 			Select(`
+				order_items.id,
+				order_items.bundle_item_id,
 				order_items.order_id,
 				products.name AS product_name,
 				order_items.quantity,
@@ -101,6 +108,7 @@ func ViewOrders(c *gin.Context) {
 				products.image_url`).
 			Joins("JOIN products ON products.id = order_items.product_id").
 			Where("order_items.order_id IN ?", orderIDs).
+			Order("order_items.id ASC").
 			Scan(&items).Error
 
 		if err != nil {
@@ -219,6 +227,8 @@ func GetOrderItemsDetail(c *gin.Context) {
 	var orderItems []OrderItemResponse
 	err = config.DB.Table("order_items").
 		Select(`
+			order_items.id,
+			order_items.bundle_item_id,
 			products.name AS product_name,
 			order_items.quantity,
 			order_items.price,
@@ -226,6 +236,7 @@ func GetOrderItemsDetail(c *gin.Context) {
 			products.image_url`).
 		Joins("JOIN products ON products.id = order_items.product_id").
 		Where("order_items.order_id = ?", orderID).
+		Order("order_items.id ASC").
 		Scan(&orderItems).Error
 
 	if err != nil {
@@ -251,16 +262,22 @@ func GetOrderItemsDetail(c *gin.Context) {
 			Email: orderDetail.BuyerEmail,
 			Phone: orderDetail.BuyerPhone,
 		},
-		Items: orderItems,
+		Items:    orderItems,
+		Subtotal: order.Subtotal,
+		Discount: order.Discount,
+		Shipping: order.Shipping,
+		Tax:      order.Tax,
 	}
 
 	// Step 7: Send Response
 	helper.SendSuccess(c, http.StatusOK, "Order details fetched successfully", finalResponse)
 }
 
+var errOrderRejected = errors.New("rejected orders can't be reopened")
+
 // UpdateOrderStatus updates the status of an order by admin
 // @Summary Update Order Status
-// @Description Admin can update the status of an order (accept, reject, ontheway, finish)
+// @Description Admin can update the status of an order (accept, reject, ontheway, finish). Rejecting an order returns its stock, and rejected orders can't be reopened.
 // @Tags Admin Orders
 // @Accept json
 // @Produce json
@@ -284,13 +301,6 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	// Fetch the order
-	var order models.Order
-	if err := config.DB.First(&order, orderID).Error; err != nil {
-		helper.SendError(c, http.StatusBadRequest, []string{"Order not found"})
-		return
-	}
-
 	// Validate allowed status transitions (optional but recommended)
 	validStatuses := map[string]bool{
 		"accept":   true,
@@ -303,9 +313,60 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	// Update order status
-	order.Status = req.Status
-	if err := config.DB.Save(&order).Error; err != nil {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID := uint64(claims["userid"].(float64))
+
+	// Update order status, returning the stock of rejected orders. The order
+	// stays locked so concurrent rejections return the stock only once.
+	var order models.Order
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			return err
+		}
+
+		// Stock of a rejected order has been put back, reopening it would oversell
+		if order.Status == "reject" && req.Status != "reject" {
+			return errOrderRejected
+		}
+
+		previousStatus := order.Status
+		order.Status = req.Status
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+		if req.Status != "reject" || previousStatus == "reject" {
+			return nil
+		}
+
+		// A bundle's stock was taken from its components, which are returned instead
+		var items []models.OrderItem
+		if err := tx.Where("order_id = ?", order.ID).
+			Where("NOT EXISTS (SELECT 1 FROM order_items components WHERE components.bundle_item_id = order_items.id)").
+			Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := models.ApplyStockMovement(tx, &models.StockMovement{
+				ProductID:   item.ProductID,
+				Delta:       item.Quantity,
+				Reason:      models.StockReasonCancellation,
+				ReferenceID: fmt.Sprintf("order:%d", order.ID),
+				ActorID:     &adminID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusBadRequest, []string{"Order not found"})
+		return
+	}
+	if errors.Is(err, errOrderRejected) {
+		helper.SendError(c, http.StatusBadRequest, []string{"Rejected orders can't be reopened"})
+		return
+	}
+	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update order status"})
 		return
 	}
@@ -0,0 +1,33 @@
+package admin
+
+import "time"
+
+type CreateSalePriceRequest struct {
+	SalePrice float64   `json:"sale_price" binding:"required,gt=0" example:"7.5"`
+	StartsAt  time.Time `json:"starts_at" binding:"required" example:"2024-12-01T00:00:00+07:00"`
+	EndsAt    time.Time `json:"ends_at" binding:"required" example:"2024-12-02T00:00:00+07:00"`
+}
+
+type SalePriceResponse struct {
+	ID        uint64  `json:"id"`
+	ProductID uint64  `json:"product_id"`
+	SalePrice float64 `json:"sale_price"`
+	StartsAt  string  `json:"starts_at"`
+	EndsAt    string  `json:"ends_at"`
+	State     string  `json:"state" example:"scheduled"` // scheduled, active or ended
+	CreatedBy *uint64 `json:"created_by"`
+	CreatedAt string  `json:"created_at"`
+}
+
+type PriceChangeResponse struct {
+	ID          uint64  `json:"id"`
+	Type        string  `json:"type" example:"regular"` // regular, sale or sale_cancelled
+	OldPrice    float64 `json:"old_price"`
+	NewPrice    float64 `json:"new_price"`
+	SalePriceID *uint64 `json:"sale_price_id"`
+	StartsAt    *string `json:"starts_at"`
+	EndsAt      *string `json:"ends_at"`
+	ActorID     *uint64 `json:"actor_id"`
+	ActorEmail  string  `json:"actor_email"`
+	CreatedAt   string  `json:"created_at"`
+}
@@ -0,0 +1,319 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// @Summary Schedule a sale price
+// @Description Admin schedules a sale price for a product between two times. Buyers pay the sale price in that window and see the regular price as the compare-at price. Sales of a product can't overlap.
+// @Tags Admin Pricing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body CreateSalePriceRequest true "Sale price and period"
+// @Success 201 {object} helper.SuccessResponse{data=SalePriceResponse} "Sale price scheduled successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 409 {object} helper.ErrorResponse "Overlaps another sale"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/sale-prices [post]
+func CreateSalePrice(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var req CreateSalePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input", err.Error()})
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		helper.SendError(c, http.StatusBadRequest, []string{"ends_at must be after starts_at"})
+		return
+	}
+	if !req.EndsAt.After(time.Now()) {
+		helper.SendError(c, http.StatusBadRequest, []string{"ends_at must be in the future"})
+		return
+	}
+
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID := uint64(claims["userid"].(float64))
+
+	sale := models.ProductSalePrice{
+		ProductID: productID,
+		SalePrice: req.SalePrice,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: &adminID,
+	}
+	var conflict, tooHigh bool
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Locking the product serialises scheduling so overlaps can't slip in
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+			return err
+		}
+		if req.SalePrice >= product.Price {
+			tooHigh = true
+			return nil
+		}
+
+		var overlapping int64
+		if err := tx.Model(&models.ProductSalePrice{}).
+			Where("product_id = ? AND starts_at < ? AND ends_at > ?", productID, req.EndsAt, req.StartsAt).
+			Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			conflict = true
+			return nil
+		}
+
+		if err := tx.Omit("Product").Create(&sale).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ProductPriceChange{
+			ProductID:   productID,
+			Type:        models.PriceChangeSale,
+			OldPrice:    product.Price,
+			NewPrice:    sale.SalePrice,
+			SalePriceID: &sale.ID,
+			StartsAt:    &sale.StartsAt,
+			EndsAt:      &sale.EndsAt,
+			ActorID:     &adminID,
+		}).Error
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to schedule sale price"})
+		return
+	case tooHigh:
+		helper.SendError(c, http.StatusBadRequest, []string{"Sale price must be lower than the regular price"})
+		return
+	case conflict:
+		helper.SendError(c, http.StatusConflict, []string{"The period overlaps another sale of this product"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Sale price scheduled successfully", _mapSalePrice(sale, time.Now()))
+}
+
+// @Summary Get sale prices
+// @Description Admin lists the past, running and scheduled sales of a product
+// @Tags Admin Pricing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} helper.SuccessResponse{data=[]SalePriceResponse} "List of sale prices"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/sale-prices [get]
+func GetSalePrices(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var sales []models.ProductSalePrice
+	if err := config.DB.Where("product_id = ?", productID).Order("starts_at DESC").Find(&sales).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve sale prices"})
+		return
+	}
+
+	now := time.Now()
+	response := make([]SalePriceResponse, len(sales))
+	for i, sale := range sales {
+		response[i] = _mapSalePrice(sale, now)
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Sale prices retrieved successfully", response)
+}
+
+// @Summary Cancel a sale price
+// @Description Admin cancels a sale. A scheduled sale is removed, a running sale ends now. Ended sales can't be cancelled.
+// @Tags Admin Pricing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param sale_id path int true "Sale price ID"
+// @Success 200 {object} helper.SuccessResponse "Sale price cancelled successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid ID or sale already ended"
+// @Failure 404 {object} helper.ErrorResponse "Sale price not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/sale-prices/{sale_id} [delete]
+func CancelSalePrice(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+	saleID, err := strconv.ParseUint(c.Param("sale_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid sale price ID"})
+		return
+	}
+
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID := uint64(claims["userid"].(float64))
+
+	var ended bool
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var sale models.ProductSalePrice
+		if err := tx.Unscoped().Preload("Product").Where("id = ? AND product_id = ?", saleID, productID).First(&sale).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !sale.EndsAt.After(now) {
+			ended = true
+			return nil
+		}
+
+		change := models.ProductPriceChange{
+			ProductID: productID,
+			Type:      models.PriceChangeSaleCancelled,
+			OldPrice:  sale.SalePrice,
+			NewPrice:  sale.Product.Price,
+			StartsAt:  &sale.StartsAt,
+			EndsAt:    &now,
+			ActorID:   &adminID,
+		}
+		if sale.StartsAt.After(now) {
+			if err := tx.Delete(&sale).Error; err != nil {
+				return err
+			}
+		} else {
+			change.SalePriceID = &sale.ID
+			if err := tx.Model(&sale).Update("ends_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&change).Error
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Sale price not found"})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to cancel sale price"})
+		return
+	case ended:
+		helper.SendError(c, http.StatusBadRequest, []string{"Sale price has already ended"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Sale price cancelled successfully", nil)
+}
+
+// @Summary Get price history
+// @Description Admin lists every regular price change, scheduled sale and cancelled sale of a product, newest first
+// @Tags Admin Pricing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]PriceChangeResponse} "Price history"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/price-history [get]
+func GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Table("product_price_changes").Where("product_price_changes.product_id = ?", productID)
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count price changes"})
+		return
+	}
+
+	var changes []PriceChangeResponse
+	err = query.
+		Select(`
+			product_price_changes.id,
+			product_price_changes.type,
+			product_price_changes.old_price,
+			product_price_changes.new_price,
+			product_price_changes.sale_price_id,
+			product_price_changes.starts_at,
+			product_price_changes.ends_at,
+			product_price_changes.actor_id,
+			COALESCE(users.email, '') AS actor_email,
+			product_price_changes.created_at`).
+		Joins("LEFT JOIN users ON users.id = product_price_changes.actor_id").
+		Order("product_price_changes.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&changes).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve price history"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Price history retrieved successfully", changes, pagination)
+}
+
+func _mapSalePrice(sale models.ProductSalePrice, now time.Time) SalePriceResponse {
+	state := "active"
+	switch {
+	case sale.StartsAt.After(now):
+		state = "scheduled"
+	case !sale.EndsAt.After(now):
+		state = "ended"
+	}
+
+	return SalePriceResponse{
+		ID:        sale.ID,
+		ProductID: sale.ProductID,
+		SalePrice: sale.SalePrice,
+		StartsAt:  sale.StartsAt.Format(time.RFC3339),
+		EndsAt:    sale.EndsAt.Format(time.RFC3339),
+		State:     state,
+		CreatedBy: sale.CreatedBy,
+		CreatedAt: sale.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -1,20 +1,40 @@
 package admin
 
 import (
+	"deketna/utils"
 	"mime/multipart"
+	"time"
 )
 
 type AddProductRequest struct {
-	Name       string                `form:"name" binding:"required"`
-	Price      float64               `form:"price" binding:"required,gt=0"`
-	Stock      int                   `form:"stock" binding:"required,gt=0"`
-	CategoryID int                   `form:"category_id" binding:"required, gt=0"`
-	Image      *multipart.FileHeader `form:"image" binding:"required"`
+	SKU               string                `form:"sku" binding:"max=64"`
+	Name              string                `form:"name" binding:"required"`
+	Price             float64               `form:"price" binding:"required,gt=0"`
+	Stock             int                   `form:"stock" binding:"required_unless=Type bundle,gte=0"`
+	ReorderThreshold  int                   `form:"reorder_threshold" binding:"gte=0"`
+	CategoryID        int                   `form:"category_id" binding:"required,gt=0"`
+	Image             *multipart.FileHeader `form:"image" binding:"required_without=UploadID"`
+	Description       string                `form:"description"`
+	DescriptionFormat string                `form:"description_format" binding:"omitempty,oneof=markdown html"`
+	Brand             string                `form:"brand" binding:"max=255"`
+	WeightGrams       float64               `form:"weight_grams" binding:"gte=0"`
+	LengthCM          float64               `form:"length_cm" binding:"gte=0"`
+	WidthCM           float64               `form:"width_cm" binding:"gte=0"`
+	HeightCM          float64               `form:"height_cm" binding:"gte=0"`
+	Attributes        string                `form:"attributes"` // JSON object of specification key to value
+	Status            string                `form:"status" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt         *time.Time            `form:"publish_at" time_format:"2006-01-02T15:04:05Z07:00"`
+	Type              string                `form:"type" binding:"omitempty,oneof=simple bundle"`
+	Components        string                `form:"components"` // JSON array of product_id and quantity, for bundles
+
+	UploadID uint64 `form:"upload_id"` // Confirmed direct upload used instead of image
 }
 
 type GetProductResponse struct {
 	ID         uint64  `json:"id" example:"1"`
+	SKU        string  `json:"sku"`
 	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
 	Price      float64 `json:"price"`
 	Stock      int     `json:"stock"`
 	SellerID   uint64  `json:"seller_id"`
@@ -22,6 +42,31 @@ type GetProductResponse struct {
 	ImageURL   string  `json:"image_url"`  // URL or path to the image
 	CreatedAt  string  `json:"created_at"` // Changed to string
 	UpdatedAt  string  `json:"updated_at"` // Changed to string
+	Status     string  `json:"status"`
+	ArchivedAt string  `json:"archived_at,omitempty" gorm:"column:deleted_at"`
+
+	Description       string  `json:"description"`
+	DescriptionFormat string  `json:"description_format"`
+	DescriptionHTML   string  `json:"description_html"`
+	Brand             string  `json:"brand"`
+	WeightGrams       float64 `json:"weight_grams"`
+	LengthCM          float64 `json:"length_cm"`
+	WidthCM           float64 `json:"width_cm"`
+	HeightCM          float64 `json:"height_cm"`
+
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int     `json:"rating_count"`
+
+	ReorderThreshold  int     `json:"reorder_threshold"`
+	LowStockAlertedAt *string `json:"low_stock_alerted_at"`
+
+	PublishAt   *string `json:"publish_at"`
+	PublishedAt *string `json:"published_at"`
+
+	Type    string `json:"type"`
+	Version int    `json:"version"` // Also sent as the ETag
+
+	PrimaryImage *ProductImageResponse `json:"primary_image,omitempty" gorm:"-"` // Renditions of a newly uploaded image
 }
 
 // Embed GetProductResponse for shared fields
@@ -31,6 +76,18 @@ type GetProductResponseComplete struct {
 	Category           Category `json:"category"`
 }
 
+// Embed GetProductResponseComplete and attach the gallery and specifications
+type GetProductDetailResponse struct {
+	GetProductResponseComplete
+	Images     []ProductImageResponse     `json:"images"`
+	Attributes []ProductAttributeResponse `json:"attributes"`
+
+	ReservedStock  int `json:"reserved_stock"`  // Held by active checkout reservations
+	AvailableStock int `json:"available_stock"` // Stock minus reserved stock
+
+	Components []BundleComponentResponse `json:"components,omitempty"` // Products a bundle contains
+}
+
 type Profile struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`
@@ -44,9 +101,23 @@ type Category struct {
 }
 
 type ProductEditRequest struct {
+	SKU        *string  `json:"sku,omitempty"`
 	Name       string   `json:"name"`
 	Price      *float64 `json:"price,omitempty"`
-	Stock      *int     `json:"stock,omitempty"`
+	StockDelta *int     `json:"stock_delta,omitempty"` // Added to the current stock
 	CategoryID *uint    `json:"category_id,omitempty"`
-	ImageURL   *string  `json:"image_url,omitempty"`
+
+	Image *utils.UploadedImage `json:"-"` // Uploaded replacement for the primary image
+
+	Description       *string           `json:"description,omitempty"`
+	DescriptionFormat *string           `json:"description_format,omitempty"`
+	Brand             *string           `json:"brand,omitempty"`
+	WeightGrams       *float64          `json:"weight_grams,omitempty"`
+	LengthCM          *float64          `json:"length_cm,omitempty"`
+	WidthCM           *float64          `json:"width_cm,omitempty"`
+	HeightCM          *float64          `json:"height_cm,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty"` // Replaces all attributes when set
+	Status            *string           `json:"status,omitempty"`
+	PublishAt         *time.Time        `json:"publish_at,omitempty"`
+	ReorderThreshold  *int              `json:"reorder_threshold,omitempty"`
 }
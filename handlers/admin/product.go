@@ -5,34 +5,51 @@ import (
 	"deketna/helper"
 	"deketna/models"
 	"deketna/utils"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // @Summary Add a product
-// @Description Admin adds a new product
+// @Description Admin adds a new product. It starts as a draft, hidden from the storefront, unless it is published or scheduled.
 // @Tags Admin Product
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
+// @Param sku formData string false "Stock keeping unit, must be unique"
 // @Param name formData string true "Product Name"
 // @Param price formData number true "Product Price"
-// @Param stock formData integer true "Product Stock"
+// @Param stock formData integer false "Product Stock, required unless the product is a bundle"
+// @Param reorder_threshold formData integer false "Alert admins when stock falls to this level or below (default: 0, no alerts)"
 // @Param category_id formData integer true "Product Category"
-// @Param image formData file true "Product Image"
+// @Param image formData file false "Product Image, required unless upload_id is given"
+// @Param upload_id formData integer false "Confirmed direct upload to use as the product image"
+// @Param description formData string false "Product description"
+// @Param description_format formData string false "Description format (markdown or html, default: markdown)"
+// @Param brand formData string false "Brand"
+// @Param weight_grams formData number false "Weight in grams"
+// @Param length_cm formData number false "Length in centimeters"
+// @Param width_cm formData number false "Width in centimeters"
+// @Param height_cm formData number false "Height in centimeters"
+// @Param attributes formData string false "Specification attributes as a JSON object, e.g. {\"volume\": 500}"
+// @Param status formData string false "Lifecycle status (draft, scheduled or published, default: draft)"
+// @Param type formData string false "Product type (simple or bundle, default: simple). Bundles take their stock from their components and ignore stock."
+// @Param components formData string false "Components of a bundle as a JSON array, e.g. [{\"product_id\": 2, \"quantity\": 1}]"
+// @Param publish_at formData string false "RFC 3339 time a scheduled product goes live, schedules the product when status is left out"
 // @Success 201 {object} helper.SuccessResponse "Product"
 // @Success 400 {object} helper.ErrorResponse "Validation Error"
 // @Failure 401 {object} helper.ErrorResponse "Unauthorized"
 // @Failure 403 {object} helper.ErrorResponse "Access forbidden"
+// @Failure 410 {object} helper.ErrorResponse "Upload expired"
 // @Router /admin/product [post]
 func AddProduct(c *gin.Context) {
 	// Parse form data
@@ -50,59 +67,198 @@ func AddProduct(c *gin.Context) {
 		return
 	}
 
-	// Handle file upload
-	file, err := c.FormFile("image")
+	categoryID := uint(req.CategoryID)
+
+	if req.SKU != "" {
+		taken, err := _isSKUTaken(config.DB, req.SKU, 0)
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to validate SKU"})
+			return
+		}
+		if taken {
+			helper.SendError(c, http.StatusBadRequest, []string{"SKU is already used by another product"})
+			return
+		}
+	}
+
+	// Validate content before uploading anything
+	descriptionHTML, err := helper.RenderDescription(req.Description, req.DescriptionFormat)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, []string{"Failed to upload image"})
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	}
+
+	// New products stay out of the storefront as drafts unless told otherwise
+	lifecycle := models.Product{Status: models.ProductStatusDraft}
+	var status *string
+	if req.Status != "" {
+		status = &req.Status
+	}
+	if err := _applyPublishing(&lifecycle, status, req.PublishAt); err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.messages)
 		return
 	}
 
-	// Ensure the tmp folder exists
-	tmpDir := "tmp"
-	if _, err := os.Stat(tmpDir); os.IsNotExist(err) {
-		err = os.Mkdir(tmpDir, os.ModePerm)
+	// Bundles are sold from their components' stock
+	if req.Type == "" {
+		req.Type = models.ProductTypeSimple
+	}
+	var components []models.BundleComponent
+	if req.Type == models.ProductTypeBundle {
+		var requested []BundleComponentRequest
+		if req.Components != "" {
+			if requested, err = _parseBundleComponents(req.Components); err != nil {
+				helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+				return
+			}
+		}
+		components, err = _validateBundleComponents(config.DB, 0, requested)
+		var vErr *validationError
+		if errors.As(err, &vErr) {
+			helper.SendError(c, http.StatusBadRequest, vErr.messages)
+			return
+		}
 		if err != nil {
-			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to create tmp directory"})
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to validate bundle components"})
 			return
 		}
+		req.Stock = 0
 	}
 
-	// Sanitize the filename
-	safeFilename := filepath.Base(file.Filename) // Prevent path traversal
-	tempFilePath := filepath.Join(tmpDir, safeFilename)
-
-	// Save the uploaded file temporarily
-	if err := c.SaveUploadedFile(file, tempFilePath); err != nil {
-		helper.SendError(c, http.StatusBadRequest, []string{"Failed to save temporary image"})
+	attributes := map[string]string{}
+	if req.Attributes != "" {
+		if attributes, err = _parseAttributes(req.Attributes); err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+			return
+		}
+	}
+	attributes, err = _validateAttributes(config.DB, &categoryID, attributes)
+	var vErr *validationError
+	if errors.As(err, &vErr) {
+		helper.SendError(c, http.StatusBadRequest, vErr.messages)
 		return
 	}
-
-	// Upload to Supabase
-	imageURL, err := utils.UploadImageToSupabase(tempFilePath, safeFilename)
-	fmt.Println("imageURL:", imageURL)
 	if err != nil {
-		// Clean up the temporary file even on upload failure
-		os.Remove(tempFilePath)
-		helper.SendError(c, http.StatusBadRequest, []string{fmt.Sprintf("Failed to upload image: %v", err)})
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to validate product attributes"})
 		return
 	}
 
-	// Remove the temporary file after successful upload
-	err = os.Remove(tempFilePath)
-	if err != nil {
-		fmt.Println("Warning: Failed to delete temporary file:", tempFilePath)
+	// The image is posted with the form or was uploaded directly to storage
+	var uploadedImage utils.UploadedImage
+	if req.UploadID != 0 {
+		uploadedImage, err = _uploadedImage(config.DB, req.UploadID)
+		if errors.As(err, &vErr) {
+			helper.SendError(c, http.StatusBadRequest, vErr.messages)
+			return
+		}
+		if errors.Is(err, errUploadGone) {
+			helper.SendError(c, http.StatusGone, []string{err.Error()})
+			return
+		}
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to look up upload"})
+			return
+		}
+	} else {
+		file, err := c.FormFile("image")
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Failed to upload image"})
+			return
+		}
+
+		// Validate, resize and upload the image renditions
+		uploadedImage, err = utils.UploadFormImage(file)
+		if errors.Is(err, utils.ErrInvalidImage) {
+			helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+			return
+		}
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{fmt.Sprintf("Failed to upload image: %v", err)})
+			return
+		}
 	}
 
 	// Create product record in DB
+	if req.DescriptionFormat == "" {
+		req.DescriptionFormat = helper.DescriptionFormatMarkdown
+	}
 	product := models.Product{
-		Name:     req.Name,
-		Price:    req.Price,
-		Stock:    req.Stock,
-		SellerID: uint64(adminID),
-		ImageURL: imageURL,
+		SKU:               req.SKU,
+		Name:              req.Name,
+		Price:             req.Price,
+		ReorderThreshold:  req.ReorderThreshold,
+		SellerID:          uint64(adminID),
+		CategoryID:        &categoryID,
+		ImageURL:          uploadedImage.URL,
+		Type:              req.Type,
+		Status:            lifecycle.Status,
+		PublishAt:         lifecycle.PublishAt,
+		PublishedAt:       lifecycle.PublishedAt,
+		Description:       req.Description,
+		DescriptionFormat: req.DescriptionFormat,
+		DescriptionHTML:   descriptionHTML,
+		Brand:             req.Brand,
+		WeightGrams:       req.WeightGrams,
+		LengthCM:          req.LengthCM,
+		WidthCM:           req.WidthCM,
+		HeightCM:          req.HeightCM,
 	}
 
-	if err := config.DB.Create(&product).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+		if err := models.AssignProductSlug(tx, &product); err != nil {
+			return err
+		}
+
+		if product.Type == models.ProductTypeBundle {
+			if err := _replaceBundleComponents(tx, product.ID, components); err != nil {
+				return err
+			}
+		}
+
+		// Initial stock enters through the inventory ledger
+		actorID := uint64(adminID)
+		movement := models.StockMovement{
+			ProductID: product.ID,
+			Delta:     req.Stock,
+			Reason:    models.StockReasonRestock,
+			ActorID:   &actorID,
+			Note:      "Initial stock",
+		}
+		if err := models.ApplyStockMovement(tx, &movement); err != nil {
+			return err
+		}
+		product.Stock = movement.BalanceAfter
+
+		if err := models.RecordPriceChange(tx, product.ID, 0, product.Price, &actorID); err != nil {
+			return err
+		}
+
+		// The uploaded image starts the product gallery as its primary image
+		image := models.ProductImage{
+			ProductID:    product.ID,
+			URL:          uploadedImage.URL,
+			MediumURL:    uploadedImage.MediumURL,
+			ThumbnailURL: uploadedImage.ThumbnailURL,
+			AltText:      req.Name,
+			IsPrimary:    true,
+		}
+		if err := tx.Create(&image).Error; err != nil {
+			return err
+		}
+		if err := models.ReferenceProductImageMedia(tx, image.ID, uploadedImage.Keys); err != nil {
+			return err
+		}
+		product.Images = []models.ProductImage{image}
+
+		if err := _replaceProductAttributes(tx, product.ID, attributes); err != nil {
+			return err
+		}
+		return models.RecordProductRevision(tx, product.ID, models.RevisionCreated, &actorID, nil)
+	})
+	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to add product to database"})
 		return
 	}
@@ -123,6 +279,8 @@ func AddProduct(c *gin.Context) {
 // @Param seller_id query int false "id of seller (default: 1)"
 // @Param seller_name query string false "Name of seller (default: Deketna)"
 // @Param product_name query string false "Name of product (default: botol)"
+// @Param category_id query int false "id of category"
+// @Param status query string false "Lifecycle status (draft, scheduled, published, unpublished or archived). Archived products are only listed when asked for."
 // @Success 200 {object} helper.PaginationResponse{data=[]GetProductResponseComplete} "List of products with seller details"
 // @Failure 400 {object} helper.ErrorResponse "Invalid query parameters"
 // @Router /admin/products [get]
@@ -131,28 +289,9 @@ func GetProduct(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "25"))
 
-	sellerIDParam := c.Query("seller_id")
-	var sellerID *uint64
-	if sellerIDParam != "" {
-		id, err := strconv.ParseUint(sellerIDParam, 10, 64)
-		if err == nil {
-			sellerID = &id
-		}
-	}
+	filter := _parseProductFilter(c)
 
-	sellerName := c.Query("seller_name")
-	var sellerNamePtr *string
-	if sellerName != "" {
-		sellerNamePtr = &sellerName
-	}
-
-	productName := c.Query("product_name")
-	var productNamePtr *string
-	if productName != "" {
-		productNamePtr = &productName
-	}
-
-	products, totalItems, err := _getProductsPaginated(config.DB, page, limit, sellerID, sellerNamePtr, productNamePtr)
+	products, totalItems, err := _getProductsPaginated(config.DB, page, limit, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
 		return
@@ -173,13 +312,14 @@ func GetProduct(c *gin.Context) {
 
 // GetProducts retrieves a detail of product with seller
 // @Summary Get Product Detail
-// @Description Retrieve a detail of products with seller
+// @Description Retrieve a detail of products with seller, in any status, so admins can preview drafts, scheduled and unpublished products
 // @Tags   Admin Product
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
-// @Success 200 {object} helper.SuccessResponse{data=GetProductResponseComplete} "List of products with seller details"
+// @Success 200 {object} helper.SuccessResponse{data=GetProductDetailResponse} "Product with seller details and gallery"
+// @Header 200 {string} ETag "Version of the product, to send back as If-Match when editing it"
 // @Failure 400 {object} helper.ErrorResponse "Invalid query parameters"
 // @Router /admin/product/{id} [get]
 func GetProductDetail(c *gin.Context) {
@@ -195,26 +335,83 @@ func GetProductDetail(c *gin.Context) {
 		return
 	}
 
-	helper.SendSuccess(c, http.StatusOK, "Products retrieved successfully", product)
+	images, err := _getProductImages(config.DB, productId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product images"})
+		return
+	}
+
+	attributes, err := _getProductAttributes(config.DB, productId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product attributes"})
+		return
+	}
+
+	// Pass 0 as no buyer is excluded, every active reservation counts
+	reserved, err := models.ReservedStock(config.DB, productId, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reserved stock"})
+		return
+	}
+
+	response := GetProductDetailResponse{
+		GetProductResponseComplete: *product,
+		Images:                     images,
+		Attributes:                 attributes,
+		ReservedStock:              reserved,
+		AvailableStock:             product.Stock - reserved,
+	}
+
+	// A bundle is as available as its components allow
+	if product.Type == models.ProductTypeBundle {
+		if response.Components, err = _getBundleComponents(config.DB, productId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bundle components"})
+			return
+		}
+		if response.AvailableStock, err = models.AvailableStock(config.DB, productId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available stock"})
+			return
+		}
+	}
+
+	c.Header("ETag", _productETag(product.Version))
+	helper.SendSuccess(c, http.StatusOK, "Products retrieved successfully", response)
 
 }
 
 // @Summary Edit a product
-// @Description Admin edit a product
+// @Description Admin edit a product. The If-Match header must hold the ETag the product was read with, edits based on an outdated copy are refused.
 // @Tags Admin Product
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID"
+// @Param If-Match header string true "ETag of the product as read from the product detail"
+// @Param sku formData string false "Stock keeping unit, must be unique"
 // @Param name formData string false "Product Name"
 // @Param price formData number false "Product Price"
-// @Param stock formData integer false "Product Stock"
+// @Param stock_delta formData integer false "Change of stock, e.g. -2 for two damaged units, recorded as an adjustment"
+// @Param reorder_threshold formData integer false "Alert admins when stock falls to this level or below, 0 disables alerts"
 // @Param category_id formData integer false "Product Category"
-// @Param image formData file false "Product Image"
+// @Param image formData file false "Product Image (replaces the primary gallery image)"
+// @Param upload_id formData integer false "Confirmed direct upload that replaces the primary gallery image"
+// @Param description formData string false "Product description"
+// @Param description_format formData string false "Description format (markdown or html)"
+// @Param brand formData string false "Brand"
+// @Param weight_grams formData number false "Weight in grams"
+// @Param length_cm formData number false "Length in centimeters"
+// @Param width_cm formData number false "Width in centimeters"
+// @Param height_cm formData number false "Height in centimeters"
+// @Param attributes formData string false "Specification attributes as a JSON object, replaces all existing attributes"
+// @Param status formData string false "Lifecycle status (draft, scheduled, published or unpublished)"
+// @Param publish_at formData string false "RFC 3339 time a scheduled product goes live, schedules the product when status is left out"
 // @Success 200 {object} helper.SuccessResponse "Product"
 // @Failure 400 {object} helper.ErrorResponse "Validation Error"
 // @Failure 401 {object} helper.ErrorResponse "Unauthorized"
 // @Failure 403 {object} helper.ErrorResponse "Access forbidden"
+// @Failure 410 {object} helper.ErrorResponse "Upload expired"
+// @Failure 412 {object} helper.ErrorResponse "Product was changed since it was read"
+// @Failure 428 {object} helper.ErrorResponse "If-Match header missing"
 // @Router /admin/product/{id} [put]
 func AdminEditProduct(c *gin.Context) {
 	// Parse Product ID
@@ -224,20 +421,44 @@ func AdminEditProduct(c *gin.Context) {
 		return
 	}
 
+	// Edits must be based on the current version of the product
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		helper.SendError(c, http.StatusPreconditionRequired, []string{"If-Match header with the product ETag is required"})
+		return
+	}
+	version, ok := _parseProductETag(ifMatch)
+	if !ok {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid If-Match header"})
+		return
+	}
+
 	// Parse Optional Form Parameters
 	var req ProductEditRequest
 
 	req.Name = c.PostForm("name")
+	if _, ok := c.GetPostForm("sku"); ok {
+		sku := c.PostForm("sku")
+		req.SKU = &sku
+	}
 	if price := c.PostForm("price"); price != "" {
 		if p, err := strconv.ParseFloat(price, 64); err == nil {
 			req.Price = &p
 		}
 	}
 
-	if stock := c.PostForm("stock"); stock != "" {
-		if s, err := strconv.Atoi(stock); err == nil {
-			req.Stock = &s
+	// Setting stock outright would overwrite sales made since it was read
+	if _, ok := c.GetPostForm("stock"); ok {
+		helper.SendError(c, http.StatusBadRequest, []string{"stock can no longer be set, send the change as stock_delta"})
+		return
+	}
+	if stockDelta := c.PostForm("stock_delta"); stockDelta != "" {
+		delta, err := strconv.Atoi(stockDelta)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid stock_delta"})
+			return
 		}
+		req.StockDelta = &delta
 	}
 
 	if categoryID := c.PostForm("category_id"); categoryID != "" {
@@ -247,43 +468,130 @@ func AdminEditProduct(c *gin.Context) {
 		}
 	}
 
-	// Handle Optional Image Upload
-	var imageURL *string
-	file, err := c.FormFile("image")
-	if err == nil && file != nil {
-		imageURL, err = _handleImageUpload(c, file)
+	if _, ok := c.GetPostForm("description"); ok {
+		description := c.PostForm("description")
+		req.Description = &description
+	}
+	if format := c.PostForm("description_format"); format != "" {
+		req.DescriptionFormat = &format
+	}
+	if threshold := c.PostForm("reorder_threshold"); threshold != "" {
+		t, err := strconv.Atoi(threshold)
+		if err != nil || t < 0 {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid reorder_threshold"})
+			return
+		}
+		req.ReorderThreshold = &t
+	}
+	if _, ok := c.GetPostForm("brand"); ok {
+		brand := c.PostForm("brand")
+		req.Brand = &brand
+	}
+	for field, target := range map[string]**float64{
+		"weight_grams": &req.WeightGrams,
+		"length_cm":    &req.LengthCM,
+		"width_cm":     &req.WidthCM,
+		"height_cm":    &req.HeightCM,
+	} {
+		if value := c.PostForm(field); value != "" {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil || f < 0 {
+				helper.SendError(c, http.StatusBadRequest, []string{fmt.Sprintf("Invalid %s", field)})
+				return
+			}
+			*target = &f
+		}
+	}
+	if status := c.PostForm("status"); status != "" {
+		req.Status = &status
+	}
+	if publishAt := c.PostForm("publish_at"); publishAt != "" {
+		t, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid publish_at: use an RFC 3339 time"})
+			return
+		}
+		req.PublishAt = &t
+	}
+	if attributes := c.PostForm("attributes"); attributes != "" {
+		if req.Attributes, err = _parseAttributes(attributes); err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+			return
+		}
+	}
+
+	// Handle Optional Image Upload, posted or uploaded directly to storage
+	if uploadID := c.PostForm("upload_id"); uploadID != "" {
+		id, err := strconv.ParseUint(uploadID, 10, 64)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid upload_id"})
+			return
+		}
+		uploadedImage, err := _uploadedImage(config.DB, id)
+		var vErr *validationError
+		if errors.As(err, &vErr) {
+			helper.SendError(c, http.StatusBadRequest, vErr.messages)
+			return
+		}
+		if errors.Is(err, errUploadGone) {
+			helper.SendError(c, http.StatusGone, []string{err.Error()})
+			return
+		}
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to look up upload"})
+			return
+		}
+		req.Image = &uploadedImage
+	} else if file, err := c.FormFile("image"); err == nil && file != nil {
+		uploadedImage, err := utils.UploadFormImage(file)
+		if errors.Is(err, utils.ErrInvalidImage) {
+			helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+			return
+		}
 		if err != nil {
 			helper.SendError(c, http.StatusInternalServerError, []string{err.Error()})
 			return
 		}
-		req.ImageURL = imageURL
+		req.Image = &uploadedImage
 	}
 
 	// Perform Product Update
-	product, err := _editProduct(config.DB, id, req)
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	product, err := _editProduct(config.DB, id, uint64(claims["userid"].(float64)), version, req, nil)
+	var vErr *validationError
+	if errors.As(err, &vErr) {
+		helper.SendError(c, http.StatusBadRequest, vErr.messages)
+		return
+	}
+	if errors.Is(err, errProductVersionConflict) {
+		helper.SendError(c, http.StatusPreconditionFailed, []string{"Product was changed by someone else, reload it and try again"})
+		return
+	}
 	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update product"})
 		return
 	}
 
 	// Success Response
+	c.Header("ETag", _productETag(product.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Product updated successfully",
 		"product": product,
 	})
 }
 
-// @Summary Delete a product
-// @Description Admin delete a product
+// @Summary Archive a product
+// @Description Admin archives a product. It disappears from the storefront but stays in order history and can be restored.
 // @Tags Admin Product
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID"
+// @Param id path int true "Product ID"
 // @Success 200 {object} helper.SuccessResponse "Product"
 // @Success 400 {object} helper.ErrorResponse "Validation Error"
 // @Failure 401 {object} helper.ErrorResponse "Unauthorized"
 // @Failure 403 {object} helper.ErrorResponse "Access forbidden"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
 // @Router /admin/product/{id} [delete]
 func AdminDeleteProduct(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -292,38 +600,178 @@ func AdminDeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := _deleteProduct(config.DB, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	err = _archiveProduct(config.DB, id, uint64(claims["userid"].(float64)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to archive product"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Product archived successfully"})
 }
 
-func _getProductsPaginated(db *gorm.DB, page, limit int, sellerID *uint64, sellerName *string, productName *string) ([]GetProductResponseComplete, int64, error) {
-	var products []GetProductResponseComplete
-	var totalItems int64
+// @Summary Restore an archived product
+// @Description Admin restores an archived product in the status it had before it was archived, a draft when that is unknown
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} helper.SuccessResponse "Product restored successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} helper.ErrorResponse "Archived product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/restore [post]
+func AdminRestoreProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
 
-	// Calculate offset
-	offset := (page - 1) * limit
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	status, err := _restoreProduct(config.DB, id, uint64(claims["userid"].(float64)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Archived product not found"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to restore product"})
+		return
+	}
 
-	query := db.Model(&models.Product{}).
-		Preload("Seller").
-		Preload("Category")
+	helper.SendSuccess(c, http.StatusOK, "Product restored successfully", gin.H{
+		"product_id": id,
+		"status":     status,
+	})
+}
 
-	if sellerID != nil {
-		query = query.Where("products.seller_id = ?", *sellerID)
+// @Summary Permanently delete a product
+// @Description Admin permanently removes an archived product with its gallery and attributes. Products referenced by orders can't be purged.
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} helper.SuccessResponse "Product purged successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 409 {object} helper.ErrorResponse "Product is not archived, is referenced by orders or is a bundle component"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/purge [delete]
+func AdminPurgeProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	err = _purgeProduct(config.DB, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	case errors.Is(err, errProductNotArchived), errors.Is(err, errProductHasOrders), errors.Is(err, errProductInBundle):
+		helper.SendError(c, http.StatusConflict, []string{err.Error()})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to purge product"})
+		return
 	}
 
-	if sellerName != nil && *sellerName != "" {
+	helper.SendSuccess(c, http.StatusOK, "Product purged successfully", nil)
+}
+
+var (
+	errProductNotArchived = errors.New("only archived products can be purged")
+	errProductHasOrders   = errors.New("product is referenced by orders and can't be purged")
+	errProductInBundle    = errors.New("product is a component of a bundle and can't be purged")
+
+	errProductVersionConflict = errors.New("product was changed since it was read")
+)
+
+// productFilter holds the admin product list filters shared by the listing and the export
+type productFilter struct {
+	SellerID    *uint64
+	SellerName  *string
+	ProductName *string
+	CategoryID  *uint
+	Status      *string
+}
+
+func _parseProductFilter(c *gin.Context) productFilter {
+	var filter productFilter
+
+	if sellerIDParam := c.Query("seller_id"); sellerIDParam != "" {
+		if id, err := strconv.ParseUint(sellerIDParam, 10, 64); err == nil {
+			filter.SellerID = &id
+		}
+	}
+
+	if sellerName := c.Query("seller_name"); sellerName != "" {
+		filter.SellerName = &sellerName
+	}
+
+	if productName := c.Query("product_name"); productName != "" {
+		filter.ProductName = &productName
+	}
+
+	if categoryIDParam := c.Query("category_id"); categoryIDParam != "" {
+		if id, err := strconv.ParseUint(categoryIDParam, 10, 64); err == nil {
+			categoryID := uint(id)
+			filter.CategoryID = &categoryID
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filter.Status = &status
+	}
+
+	return filter
+}
+
+func _applyProductFilter(query *gorm.DB, filter productFilter) *gorm.DB {
+	if filter.SellerID != nil {
+		query = query.Where("products.seller_id = ?", *filter.SellerID)
+	}
+
+	if filter.SellerName != nil && *filter.SellerName != "" {
 		query = query.Joins("JOIN profiles ON profiles.user_id = products.seller_id").
-			Where("LOWER(profiles.name) ILIKE LOWER(?)", "%"+*sellerName+"%")
+			Where("LOWER(profiles.name) ILIKE LOWER(?)", "%"+*filter.SellerName+"%")
+	}
+
+	if filter.ProductName != nil && *filter.ProductName != "" {
+		query = query.Where("LOWER(products.name) ILIKE LOWER(?)", "%"+*filter.ProductName+"%")
 	}
 
-	if productName != nil && *productName != "" {
-		query = query.Where("LOWER(name) ILIKE LOWER(?)", "%"+*productName+"%")
+	if filter.CategoryID != nil {
+		query = query.Where("products.category_id = ?", *filter.CategoryID)
 	}
 
+	if filter.Status != nil && *filter.Status == models.ProductStatusArchived {
+		query = query.Unscoped().Where("products.deleted_at IS NOT NULL")
+	} else if filter.Status != nil && *filter.Status != "" {
+		query = query.Where("products.status = ?", *filter.Status)
+	}
+
+	return query
+}
+
+func _getProductsPaginated(db *gorm.DB, page, limit int, filter productFilter) ([]GetProductResponseComplete, int64, error) {
+	var products []GetProductResponseComplete
+	var totalItems int64
+
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	query := _applyProductFilter(db.Model(&models.Product{}).
+		Preload("Seller").
+		Preload("Category"), filter)
+
 	if err := query.Count(&totalItems).Error; err != nil {
 		return nil, 0, err
 	}
@@ -339,7 +787,9 @@ func _getProductsPaginated(db *gorm.DB, page, limit int, sellerID *uint64, selle
 		response[i] = GetProductResponseComplete{
 			GetProductResponse: GetProductResponse{
 				ID:        product.ID,
+				SKU:       product.SKU,
 				Name:      product.Name,
+				Slug:      product.Slug,
 				Price:     product.Price,
 				Stock:     product.Stock,
 				SellerID:  product.SellerID,
@@ -366,7 +816,8 @@ func _getProductDetail(db *gorm.DB, productId uint64) (*GetProductResponseComple
 
 	var product GetProductResponseComplete
 
-	query := db.Model(&models.Product{}).
+	// Archived products stay visible to admins
+	query := db.Unscoped().Model(&models.Product{}).
 		Preload("Seller").
 		Preload("Category")
 
@@ -379,7 +830,9 @@ func _getProductDetail(db *gorm.DB, productId uint64) (*GetProductResponseComple
 	response := GetProductResponseComplete{
 		GetProductResponse: GetProductResponse{
 			ID:         product.ID,
+			SKU:        product.SKU,
 			Name:       product.Name,
+			Slug:       product.Slug,
 			Price:      product.Price,
 			Stock:      product.Stock,
 			SellerID:   product.SellerID,
@@ -387,6 +840,29 @@ func _getProductDetail(db *gorm.DB, productId uint64) (*GetProductResponseComple
 			ImageURL:   product.ImageURL,
 			CreatedAt:  product.CreatedAt,
 			UpdatedAt:  product.UpdatedAt,
+			Status:     product.Status,
+			ArchivedAt: product.ArchivedAt,
+
+			Description:       product.Description,
+			DescriptionFormat: product.DescriptionFormat,
+			DescriptionHTML:   product.DescriptionHTML,
+			Brand:             product.Brand,
+			WeightGrams:       product.WeightGrams,
+			LengthCM:          product.LengthCM,
+			WidthCM:           product.WidthCM,
+			HeightCM:          product.HeightCM,
+
+			RatingAverage: product.RatingAverage,
+			RatingCount:   product.RatingCount,
+
+			ReorderThreshold:  product.ReorderThreshold,
+			LowStockAlertedAt: product.LowStockAlertedAt,
+
+			PublishAt:   product.PublishAt,
+			PublishedAt: product.PublishedAt,
+
+			Type:    product.Type,
+			Version: product.Version,
 		},
 		Seller: Profile{
 			ID:       product.Seller.ID,
@@ -403,38 +879,188 @@ func _getProductDetail(db *gorm.DB, productId uint64) (*GetProductResponseComple
 	return &response, nil
 }
 
-func _editProduct(db *gorm.DB, id uint64, req ProductEditRequest) (*GetProductResponse, error) {
+// _editProduct applies req to the product if it is still at version, the
+// version the admin's copy of it was read at. restoredFrom is the revision
+// the edit rolls back to, nil for a regular edit.
+func _editProduct(db *gorm.DB, id, actorID uint64, version int, req ProductEditRequest, restoredFrom *int) (*GetProductResponse, error) {
 	var product models.Product
 
 	// Find product by ID
 	if err := db.Omit("Seller", "Category").First(&product, id).Error; err != nil {
 		return nil, err
 	}
+	if product.Version != version {
+		return nil, errProductVersionConflict
+	}
 
+	if req.SKU != nil && *req.SKU != product.SKU {
+		taken, err := _isSKUTaken(db, *req.SKU, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, &validationError{messages: []string{"SKU is already used by another product"}}
+		}
+		product.SKU = *req.SKU
+	}
+	renamed := req.Name != "" && req.Name != product.Name
 	if req.Name != "" {
 		product.Name = req.Name
 	}
+	previousPrice := product.Price
 	if req.Price != nil {
 		product.Price = *req.Price
 	}
-	if req.Stock != nil {
-		product.Stock = *req.Stock
+	// Stock is changed by a delta so sales made meanwhile aren't overwritten
+	stockDelta := 0
+	if req.StockDelta != nil {
+		if product.Type == models.ProductTypeBundle && *req.StockDelta != 0 {
+			return nil, &validationError{messages: []string{"Bundles take their stock from their components"}}
+		}
+		stockDelta = *req.StockDelta
 	}
 	if req.CategoryID != nil {
 		product.CategoryID = req.CategoryID
 	}
-	if req.ImageURL != nil {
-		product.ImageURL = *req.ImageURL
+	if req.ReorderThreshold != nil {
+		product.ReorderThreshold = *req.ReorderThreshold
+		if product.Stock > product.ReorderThreshold {
+			product.LowStockAlertedAt = nil
+		}
+	}
+	if req.Description != nil || req.DescriptionFormat != nil {
+		if req.Description != nil {
+			product.Description = *req.Description
+		}
+		if req.DescriptionFormat != nil {
+			product.DescriptionFormat = *req.DescriptionFormat
+		}
+		descriptionHTML, err := helper.RenderDescription(product.Description, product.DescriptionFormat)
+		if err != nil {
+			return nil, &validationError{messages: []string{err.Error()}}
+		}
+		product.DescriptionHTML = descriptionHTML
+	}
+	if err := _applyPublishing(&product, req.Status, req.PublishAt); err != nil {
+		return nil, err
+	}
+	if req.Brand != nil {
+		product.Brand = *req.Brand
+	}
+	if req.WeightGrams != nil {
+		product.WeightGrams = *req.WeightGrams
+	}
+	if req.LengthCM != nil {
+		product.LengthCM = *req.LengthCM
+	}
+	if req.WidthCM != nil {
+		product.WidthCM = *req.WidthCM
+	}
+	if req.HeightCM != nil {
+		product.HeightCM = *req.HeightCM
+	}
+
+	// Attributes are checked against the schema of the (possibly new) category.
+	// Existing values are re-validated when only the category changes.
+	attributes := req.Attributes
+	if attributes == nil && req.CategoryID != nil {
+		var existing []models.ProductAttribute
+		if err := db.Where("product_id = ?", product.ID).Find(&existing).Error; err != nil {
+			return nil, err
+		}
+		attributes = make(map[string]string, len(existing))
+		for _, attr := range existing {
+			attributes[attr.Key] = attr.Value
+		}
+	}
+	if attributes != nil {
+		var err error
+		if attributes, err = _validateAttributes(db, product.CategoryID, attributes); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := db.Omit("Seller", "Category").Save(&product).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Moving the version on fails when someone else changed the product
+		// since it was read, and locks it until the edit is saved
+		result := tx.Model(&models.Product{}).
+			Where("id = ? AND version = ?", product.ID, version).
+			UpdateColumn("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errProductVersionConflict
+		}
+		product.Version = version + 1
+
+		// Stock is left to the ledger, which records the admin's correction.
+		// Columns kept up to date by sales, reviews and the gallery aren't
+		// versioned and are left alone.
+		omit := []string{"Seller", "Category", "Images", "Attributes", "Stock", "RatingAverage", "RatingCount", "ImageURL"}
+		if req.ReorderThreshold == nil {
+			omit = append(omit, "LowStockAlertedAt")
+		}
+		if err := tx.Omit(omit...).Save(&product).Error; err != nil {
+			return err
+		}
+		movement := models.StockMovement{
+			ProductID: product.ID,
+			Delta:     stockDelta,
+			Reason:    models.StockReasonAdjustment,
+			ActorID:   &actorID,
+			Note:      "Stock edited by admin",
+		}
+		err := models.ApplyStockMovement(tx, &movement)
+		if errors.Is(err, models.ErrInsufficientStock) {
+			return &validationError{messages: []string{"Stock can't go below zero"}}
+		}
+		if err != nil {
+			return err
+		}
+		if stockDelta != 0 {
+			product.Stock = movement.BalanceAfter
+		}
+		if err := models.RecordPriceChange(tx, product.ID, previousPrice, product.Price, &actorID); err != nil {
+			return err
+		}
+		if renamed {
+			if err := models.AssignProductSlug(tx, &product); err != nil {
+				return err
+			}
+		}
+		if attributes != nil {
+			if err := _replaceProductAttributes(tx, product.ID, attributes); err != nil {
+				return err
+			}
+		}
+		if req.Image != nil {
+			if err := _replacePrimaryImage(tx, product.ID, *req.Image, product.Name); err != nil {
+				return err
+			}
+			// Syncing the gallery moved the version on once more
+			product.Version++
+		}
+
+		action := models.RevisionUpdated
+		if restoredFrom != nil {
+			action = models.RevisionRolledBack
+		}
+		return models.RecordProductRevision(tx, product.ID, action, &actorID, restoredFrom)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if req.Image != nil {
+		product.ImageURL = req.Image.URL
+	}
 
 	// Map to DTO
 	response := GetProductResponse{
 		ID:         product.ID,
+		SKU:        product.SKU,
 		Name:       product.Name,
+		Slug:       product.Slug,
 		Price:      product.Price,
 		Stock:      product.Stock,
 		SellerID:   product.SellerID,
@@ -442,51 +1068,213 @@ func _editProduct(db *gorm.DB, id uint64, req ProductEditRequest) (*GetProductRe
 		ImageURL:   product.ImageURL,
 		CreatedAt:  product.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:  product.UpdatedAt.Format(time.RFC3339),
+		Status:     product.Status,
+
+		Description:       product.Description,
+		DescriptionFormat: product.DescriptionFormat,
+		DescriptionHTML:   product.DescriptionHTML,
+		Brand:             product.Brand,
+		WeightGrams:       product.WeightGrams,
+		LengthCM:          product.LengthCM,
+		WidthCM:           product.WidthCM,
+		HeightCM:          product.HeightCM,
+
+		RatingAverage: product.RatingAverage,
+		RatingCount:   product.RatingCount,
+
+		ReorderThreshold: product.ReorderThreshold,
+	}
+	if product.LowStockAlertedAt != nil {
+		alertedAt := product.LowStockAlertedAt.Format(time.RFC3339)
+		response.LowStockAlertedAt = &alertedAt
+	}
+	response.Type = product.Type
+	response.Version = product.Version
+	if product.PublishAt != nil {
+		publishAt := product.PublishAt.Format(time.RFC3339)
+		response.PublishAt = &publishAt
+	}
+	if product.PublishedAt != nil {
+		publishedAt := product.PublishedAt.Format(time.RFC3339)
+		response.PublishedAt = &publishedAt
+	}
+	if req.Image != nil {
+		images, err := _getProductImages(db, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range images {
+			if images[i].IsPrimary {
+				response.PrimaryImage = &images[i]
+			}
+		}
 	}
 
 	return &response, nil
 }
 
-func _deleteProduct(db *gorm.DB, id uint64) error {
-	// Delete product by ID
-	if err := db.Delete(&models.Product{}, id).Error; err != nil {
-		return err
+// _productETag is the ETag of a product at version
+func _productETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// _parseProductETag reads the version back from a product ETag, weak or not
+func _parseProductETag(etag string) (int, bool) {
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(strings.TrimSpace(etag), "W/"))
+	if err != nil {
+		return 0, false
 	}
-	return nil
+	version, err := strconv.Atoi(unquoted)
+	return version, err == nil
 }
 
-func _handleImageUpload(c *gin.Context, file *multipart.FileHeader) (*string, error) {
-	// Ensure the tmp folder exists
-	tmpDir := "tmp"
-	if _, err := os.Stat(tmpDir); os.IsNotExist(err) {
-		err = os.Mkdir(tmpDir, os.ModePerm)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create tmp directory: %v", err)
+// _applyPublishing moves product to status, or schedules it when only a
+// publish time is given. Scheduled products need a publish time in the future,
+// the other states drop it.
+func _applyPublishing(product *models.Product, status *string, publishAt *time.Time) *validationError {
+	if status == nil && publishAt == nil {
+		return nil
+	}
+	next := models.ProductStatusScheduled
+	if status != nil {
+		next = *status
+	}
+
+	switch next {
+	case models.ProductStatusDraft, models.ProductStatusPublished, models.ProductStatusUnpublished:
+		if publishAt != nil {
+			return &validationError{messages: []string{"publish_at only applies to scheduled products"}}
 		}
+		if next == models.ProductStatusPublished && product.Status != models.ProductStatusPublished {
+			now := time.Now()
+			product.PublishedAt = &now
+		}
+		product.PublishAt = nil
+	case models.ProductStatusScheduled:
+		if publishAt == nil {
+			publishAt = product.PublishAt
+		}
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return &validationError{messages: []string{"Scheduled products need a publish_at in the future"}}
+		}
+		product.PublishAt = publishAt
+	default:
+		return &validationError{messages: []string{"Invalid status: use draft, scheduled, published or unpublished, archive through DELETE"}}
 	}
 
-	// Sanitize the filename to prevent path traversal
-	safeFilename := filepath.Base(file.Filename)
-	tempFilePath := filepath.Join(tmpDir, safeFilename)
+	product.Status = next
+	return nil
+}
 
-	// Save the uploaded file to the tmp directory
-	err := c.SaveUploadedFile(file, tempFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save temporary image: %v", err)
+// _isSKUTaken reports whether another product, archived ones included, uses the SKU
+func _isSKUTaken(db *gorm.DB, sku string, exceptID uint64) (bool, error) {
+	if sku == "" {
+		return false, nil
 	}
+	var count int64
+	err := db.Unscoped().Model(&models.Product{}).
+		Where("sku = ? AND id <> ?", sku, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
 
-	// Upload to Supabase
-	imageURL, err := utils.UploadImageToSupabase(tempFilePath, safeFilename)
-	if err != nil {
-		// Clean up temporary file even on upload failure
-		os.Remove(tempFilePath)
-		return nil, fmt.Errorf("failed to upload image to Supabase: %v", err)
-	}
+func _archiveProduct(db *gorm.DB, id, actorID uint64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("id = ?", id).
+			UpdateColumn("status", models.ProductStatusArchived)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
 
-	// Remove the temporary file after successful upload
-	if err := os.Remove(tempFilePath); err != nil {
-		fmt.Println("Warning: Failed to delete temporary file:", tempFilePath)
-	}
+		// Soft delete keeps the row for order history
+		if err := tx.Delete(&models.Product{}, id).Error; err != nil {
+			return err
+		}
+		return models.RecordProductRevision(tx, id, models.RevisionArchived, &actorID, nil)
+	})
+}
+
+// _restoreProduct brings an archived product back in the status it had
+// before it was archived, as recorded by its revisions. Products archived
+// before revisions existed come back as drafts to be reviewed.
+func _restoreProduct(db *gorm.DB, id, actorID uint64) (string, error) {
+	status := models.ProductStatusDraft
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			First(&product).Error; err != nil {
+			return err
+		}
+
+		var revisions []models.ProductRevision
+		if err := tx.Where("product_id = ? AND snapshot->>'status' <> ?", id, models.ProductStatusArchived).
+			Order("revision DESC").
+			Limit(1).
+			Find(&revisions).Error; err != nil {
+			return err
+		}
+		if len(revisions) > 0 {
+			var snapshot models.ProductSnapshot
+			if err := json.Unmarshal([]byte(revisions[0].Snapshot), &snapshot); err != nil {
+				return err
+			}
+			if snapshot.Status != "" {
+				status = snapshot.Status
+			}
+		}
+
+		columns := map[string]interface{}{
+			"status":     status,
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}
+		if status == models.ProductStatusPublished {
+			columns["published_at"] = gorm.Expr("NOW()")
+		}
+		if err := tx.Unscoped().Model(&models.Product{}).Where("id = ?", id).UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+		return models.RecordProductRevision(tx, id, models.RevisionUnarchived, &actorID, nil)
+	})
+	return status, err
+}
+
+func _purgeProduct(db *gorm.DB, id uint64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+			return err
+		}
+		if !product.DeletedAt.Valid {
+			return errProductNotArchived
+		}
 
-	return &imageURL, nil
+		var orderItems int64
+		if err := tx.Model(&models.OrderItem{}).Where("product_id = ?", id).Count(&orderItems).Error; err != nil {
+			return err
+		}
+		if orderItems > 0 {
+			return errProductHasOrders
+		}
+
+		var bundles int64
+		if err := tx.Model(&models.BundleComponent{}).Where("component_id = ?", id).Count(&bundles).Error; err != nil {
+			return err
+		}
+		if bundles > 0 {
+			return errProductInBundle
+		}
+
+		if err := tx.Where("product_id = ?", id).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+
+		// Gallery and attributes go with the row through ON DELETE CASCADE
+		return tx.Unscoped().Delete(&models.Product{}, id).Error
+	})
 }
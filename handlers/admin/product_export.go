@@ -0,0 +1,223 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// productExportRow is one exported product in the productFileColumns layout
+type productExportRow struct {
+	ID          uint64  `json:"id"`
+	SKU         string  `json:"sku"`
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Stock       int     `json:"stock"`
+	Category    string  `json:"category"`
+	ImageURL    string  `json:"image_url"`
+	Brand       string  `json:"brand"`
+	Description string  `json:"description"`
+}
+
+func (r productExportRow) values() []string {
+	return []string{
+		strconv.FormatUint(r.ID, 10),
+		r.SKU,
+		r.Name,
+		strconv.FormatFloat(r.Price, 'f', -1, 64),
+		strconv.Itoa(r.Stock),
+		r.Category,
+		r.ImageURL,
+		r.Brand,
+		r.Description,
+	}
+}
+
+// @Summary Export products
+// @Description Admin exports the catalog as CSV, XLSX or JSON. The file uses the same columns as the product import, so it can be edited and imported back in any of the three formats, and is streamed row by row.
+// @Tags Admin Product
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format (csv, xlsx or json, default: csv)"
+// @Param seller_id query int false "id of seller"
+// @Param seller_name query string false "Name of seller"
+// @Param product_name query string false "Name of product"
+// @Param category_id query int false "id of category"
+// @Param status query string false "Lifecycle status (draft, active or archived)"
+// @Success 200 {file} file "Product catalog"
+// @Failure 400 {object} helper.ErrorResponse "Invalid format"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/export [get]
+func ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", productFileCSV)
+	if format != productFileCSV && format != productFileXLSX && format != productFileJSON {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid format: use csv, xlsx or json"})
+		return
+	}
+
+	query := _applyProductFilter(config.DB.Model(&models.Product{}), _parseProductFilter(c)).
+		Select(`
+			products.id,
+			products.sku,
+			products.name,
+			products.price,
+			products.stock,
+			COALESCE(categories.name, '') AS category,
+			products.image_url,
+			products.brand,
+			products.description`).
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Order("products.id ASC")
+
+	rows, err := query.Rows()
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to export products"})
+		return
+	}
+	defer rows.Close()
+
+	// Rows are read one at a time so the catalog never sits in memory as a whole
+	next := func() (*productExportRow, error) {
+		if !rows.Next() {
+			return nil, rows.Err()
+		}
+		var row productExportRow
+		if err := config.DB.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		return &row, nil
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	switch format {
+	case productFileCSV:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		err = _streamProductsCSV(c, next)
+	case productFileXLSX:
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		err = _streamProductsXLSX(c, next)
+	case productFileJSON:
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = _streamProductsJSON(c, next)
+	}
+
+	// Headers are already sent at this point, all we can do is log
+	if err != nil {
+		log.Printf("product export failed: %v", err)
+	}
+}
+
+func _streamProductsCSV(c *gin.Context, next func() (*productExportRow, error)) error {
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(productFileColumns); err != nil {
+		return err
+	}
+
+	for count := 1; ; count++ {
+		row, err := next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		if err := writer.Write(row.values()); err != nil {
+			return err
+		}
+		if count%500 == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func _streamProductsJSON(c *gin.Context, next func() (*productExportRow, error)) error {
+	if _, err := c.Writer.WriteString("["); err != nil {
+		return err
+	}
+
+	for count := 0; ; count++ {
+		row, err := next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			c.Writer.WriteString(",")
+		}
+		if _, err := c.Writer.Write(encoded); err != nil {
+			return err
+		}
+		if count%500 == 0 {
+			c.Writer.Flush()
+		}
+	}
+
+	_, err := c.Writer.WriteString("]")
+	return err
+}
+
+// _streamProductsXLSX uses the excelize stream writer, which spills rows to a
+// temporary file instead of keeping the whole sheet in memory
+func _streamProductsXLSX(c *gin.Context, next func() (*productExportRow, error)) error {
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	sheet := workbook.GetSheetName(0)
+	stream, err := workbook.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(productFileColumns))
+	for i, column := range productFileColumns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for line := 2; ; line++ {
+		row, err := next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, line)
+		if err := stream.SetRow(cell, []interface{}{
+			row.ID, row.SKU, row.Name, row.Price, row.Stock, row.Category, row.ImageURL, row.Brand, row.Description,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return workbook.Write(c.Writer)
+}
@@ -0,0 +1,192 @@
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// File formats understood by the product import and export
+const (
+	productFileCSV  = "csv"
+	productFileXLSX = "xlsx"
+	productFileJSON = "json" // An array of objects keyed by column name
+)
+
+// productFileColumns is the column layout shared by the product import and
+// export, so an exported file can be edited and imported back as is. The id
+// column matches products that have no SKU.
+var productFileColumns = []string{"id", "sku", "name", "price", "stock", "category", "image_url", "brand", "description"}
+
+// productFileRequiredColumns must be present in an imported file
+var productFileRequiredColumns = []string{"name", "price", "stock"}
+
+// productFileRecord is one data row of an imported file keyed by column name
+type productFileRecord struct {
+	Line   int // 1-based line in the file, the header being line 1. In JSON files the position of the object in the array.
+	Values map[string]string
+}
+
+// Has reports whether the file has the column at all, as opposed to an empty cell
+func (r productFileRecord) Has(column string) bool {
+	_, ok := r.Values[column]
+	return ok
+}
+
+func (r productFileRecord) Get(column string) string {
+	return strings.TrimSpace(r.Values[column])
+}
+
+// _productFileFormat picks the file format from an explicit value or the file extension
+func _productFileFormat(format, filename string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch format {
+	case productFileCSV, productFileXLSX, productFileJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported file format %q, use csv, xlsx or json", format)
+	}
+}
+
+// _readProductFile parses a CSV, XLSX or JSON product file. The first row
+// holds the column names; unknown columns are ignored.
+func _readProductFile(r io.Reader, format string) ([]productFileRecord, error) {
+	var rows [][]string
+	switch format {
+	case productFileCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		var err error
+		if rows, err = reader.ReadAll(); err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %v", err)
+		}
+	case productFileXLSX:
+		workbook, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XLSX: %v", err)
+		}
+		defer workbook.Close()
+
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("XLSX file has no sheets")
+		}
+		if rows, err = workbook.GetRows(sheets[0]); err != nil {
+			return nil, fmt.Errorf("failed to read XLSX: %v", err)
+		}
+	case productFileJSON:
+		var err error
+		if rows, err = _jsonProductRows(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file format %q", format)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	header := make([]string, len(rows[0]))
+	present := make(map[string]bool, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		present[header[i]] = true
+	}
+	var missing []string
+	for _, column := range productFileRequiredColumns {
+		if !present[column] {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	firstLine := 2
+	if format == productFileJSON {
+		firstLine = 1
+	}
+
+	var records []productFileRecord
+	for i, row := range rows[1:] {
+		record := productFileRecord{Line: firstLine + i, Values: make(map[string]string, len(header))}
+		empty := true
+		for j, column := range header {
+			if column == "" {
+				continue
+			}
+			value := ""
+			if j < len(row) {
+				value = row[j]
+			}
+			if strings.TrimSpace(value) != "" {
+				empty = false
+			}
+			record.Values[column] = value
+		}
+		// Trailing blank lines are common in spreadsheets
+		if empty {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// _jsonProductRows turns a JSON array of product objects, as the JSON export
+// writes them, into a header row followed by one row per object
+func _jsonProductRows(r io.Reader) ([][]string, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var objects []map[string]interface{}
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %v", err)
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var header []string
+	for _, object := range objects {
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+	sort.Strings(header)
+
+	rows := [][]string{header}
+	for i, object := range objects {
+		row := make([]string, len(header))
+		for j, column := range header {
+			switch value := object[column].(type) {
+			case nil:
+			case string:
+				row[j] = value
+			case json.Number:
+				row[j] = value.String()
+			case bool:
+				row[j] = fmt.Sprint(value)
+			default:
+				return nil, fmt.Errorf("JSON product %d: %s must be a string or a number", i+1, column)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,22 @@
+package admin
+
+type ProductImageResponse struct {
+	ID        uint64 `json:"id" example:"1"`
+	URL       string `json:"url"` // Large rendition
+	AltText   string `json:"alt_text"`
+	Position  int    `json:"position"`
+	IsPrimary bool   `json:"is_primary"`
+
+	MediumURL    string `json:"medium_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+type ReorderProductImagesRequest struct {
+	ImageIDs       []uint64 `json:"image_ids" binding:"required,min=1"` // Image IDs in their new display order
+	PrimaryImageID *uint64  `json:"primary_image_id,omitempty"`
+}
+
+type UpdateProductImageRequest struct {
+	AltText   *string `json:"alt_text,omitempty" binding:"omitempty,max=255"`
+	IsPrimary *bool   `json:"is_primary,omitempty"`
+}
@@ -0,0 +1,445 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"deketna/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Upload product images
+// @Description Admin uploads one or more JPEG, PNG, GIF or WebP images to a product gallery. Images are appended after the existing ones. Each is stripped of its metadata and stored as WebP thumbnail, medium and large renditions.
+// @Tags Admin Product
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param images formData file false "Product Images (repeat the field to upload several)"
+// @Param upload_id formData integer false "Confirmed direct uploads to add after the posted images (repeat the field to add several)"
+// @Param alt_text formData string false "Alt text, one value per image in the same order, posted images first"
+// @Success 201 {object} helper.SuccessResponse{data=[]ProductImageResponse} "Product gallery"
+// @Failure 400 {object} helper.ErrorResponse "Validation Error"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 410 {object} helper.ErrorResponse "Upload expired"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/images [post]
+func AddProductImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var product models.Product
+	if err := config.DB.Select("id").First(&product, productID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"])+len(form.Value["upload_id"]) == 0 {
+		helper.SendError(c, http.StatusBadRequest, []string{"At least one image is required"})
+		return
+	}
+	files := form.File["images"]
+	uploadIDs := form.Value["upload_id"]
+	altTexts := form.Value["alt_text"]
+
+	// Upload everything first so a failed upload doesn't leave a half-written gallery
+	var uploadedImages []utils.UploadedImage
+	for _, file := range files {
+		uploadedImage, err := utils.UploadFormImage(file)
+		if errors.Is(err, utils.ErrInvalidImage) {
+			helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+			return
+		}
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{err.Error()})
+			return
+		}
+		uploadedImages = append(uploadedImages, uploadedImage)
+	}
+
+	// Direct uploads come after the posted images
+	for _, value := range uploadIDs {
+		uploadID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid upload_id"})
+			return
+		}
+		uploadedImage, err := _uploadedImage(config.DB, uploadID)
+		var vErr *validationError
+		if errors.As(err, &vErr) {
+			helper.SendError(c, http.StatusBadRequest, vErr.messages)
+			return
+		}
+		if errors.Is(err, errUploadGone) {
+			helper.SendError(c, http.StatusGone, []string{err.Error()})
+			return
+		}
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to look up upload"})
+			return
+		}
+		uploadedImages = append(uploadedImages, uploadedImage)
+	}
+
+	var uploaded []models.ProductImage
+	var mediaKeys [][]string
+	for i, uploadedImage := range uploadedImages {
+		image := models.ProductImage{
+			ProductID:    productID,
+			URL:          uploadedImage.URL,
+			MediumURL:    uploadedImage.MediumURL,
+			ThumbnailURL: uploadedImage.ThumbnailURL,
+		}
+		if i < len(altTexts) {
+			image.AltText = altTexts[i]
+		}
+		uploaded = append(uploaded, image)
+		mediaKeys = append(mediaKeys, uploadedImage.Keys)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		if err := tx.Model(&models.ProductImage{}).
+			Where("product_id = ?", productID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+
+		for i := range uploaded {
+			uploaded[i].Position = maxPosition + 1 + i
+		}
+		if err := tx.Create(&uploaded).Error; err != nil {
+			return err
+		}
+		for i := range uploaded {
+			if err := models.ReferenceProductImageMedia(tx, uploaded[i].ID, mediaKeys[i]); err != nil {
+				return err
+			}
+		}
+
+		return _syncPrimaryImage(tx, productID)
+	})
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to save product images"})
+		return
+	}
+
+	images, err := _getProductImages(config.DB, productID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch product images"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Product images uploaded successfully", images)
+}
+
+// @Summary Reorder product images
+// @Description Admin sets the display order of a product gallery and optionally picks the primary image
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param payload body ReorderProductImagesRequest true "Image IDs in display order"
+// @Success 200 {object} helper.SuccessResponse{data=[]ProductImageResponse} "Product gallery"
+// @Failure 400 {object} helper.ErrorResponse "Validation Error"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/images/reorder [put]
+func ReorderProductImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var req ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var existingIDs []uint64
+		if err := tx.Model(&models.ProductImage{}).
+			Where("product_id = ?", productID).
+			Pluck("id", &existingIDs).Error; err != nil {
+			return err
+		}
+
+		// The new order must mention every image of the product exactly once
+		existing := make(map[uint64]bool, len(existingIDs))
+		for _, id := range existingIDs {
+			existing[id] = true
+		}
+		if len(req.ImageIDs) != len(existingIDs) {
+			return errInvalidImageOrder
+		}
+		seen := make(map[uint64]bool, len(req.ImageIDs))
+		for _, id := range req.ImageIDs {
+			if !existing[id] || seen[id] {
+				return errInvalidImageOrder
+			}
+			seen[id] = true
+		}
+		if req.PrimaryImageID != nil && !existing[*req.PrimaryImageID] {
+			return errInvalidImageOrder
+		}
+
+		for position, id := range req.ImageIDs {
+			if err := tx.Model(&models.ProductImage{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		if req.PrimaryImageID != nil {
+			if err := _setPrimaryImage(tx, productID, *req.PrimaryImageID); err != nil {
+				return err
+			}
+		}
+
+		return _syncPrimaryImage(tx, productID)
+	})
+	if errors.Is(err, errInvalidImageOrder) {
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to reorder product images"})
+		return
+	}
+
+	images, err := _getProductImages(config.DB, productID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch product images"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product images reordered successfully", images)
+}
+
+// @Summary Update a product image
+// @Description Admin updates the alt text of a gallery image or marks it as the primary image
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param image_id path int true "Image ID"
+// @Param payload body UpdateProductImageRequest true "Image fields to update"
+// @Success 200 {object} helper.SuccessResponse{data=[]ProductImageResponse} "Product gallery"
+// @Failure 400 {object} helper.ErrorResponse "Validation Error"
+// @Failure 404 {object} helper.ErrorResponse "Image not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/images/{image_id} [put]
+func UpdateProductImage(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid image ID"})
+		return
+	}
+
+	var req UpdateProductImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	var image models.ProductImage
+	if err := config.DB.Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Image not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if req.AltText != nil {
+			if err := tx.Model(&image).Update("alt_text", *req.AltText).Error; err != nil {
+				return err
+			}
+		}
+		if req.IsPrimary != nil && *req.IsPrimary {
+			if err := _setPrimaryImage(tx, productID, imageID); err != nil {
+				return err
+			}
+		}
+		return _syncPrimaryImage(tx, productID)
+	})
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update product image"})
+		return
+	}
+
+	images, err := _getProductImages(config.DB, productID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch product images"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product image updated successfully", images)
+}
+
+// @Summary Delete a product image
+// @Description Admin removes an image from a product gallery. When the primary image is removed the next image becomes primary.
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param image_id path int true "Image ID"
+// @Success 200 {object} helper.SuccessResponse{data=[]ProductImageResponse} "Product gallery"
+// @Failure 400 {object} helper.ErrorResponse "Validation Error"
+// @Failure 404 {object} helper.ErrorResponse "Image not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/product/{id}/images/{image_id} [delete]
+func DeleteProductImage(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid image ID"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND product_id = ?", imageID, productID).Delete(&models.ProductImage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return _syncPrimaryImage(tx, productID)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Image not found"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to delete product image"})
+		return
+	}
+
+	images, err := _getProductImages(config.DB, productID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to fetch product images"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product image deleted successfully", images)
+}
+
+var errInvalidImageOrder = errors.New("image_ids must list every image of the product exactly once")
+
+func _getProductImages(db *gorm.DB, productID uint64) ([]ProductImageResponse, error) {
+	var images []models.ProductImage
+	if err := db.Where("product_id = ?", productID).
+		Order("position ASC, id ASC").
+		Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	// Map to DTO
+	response := make([]ProductImageResponse, len(images))
+	for i, image := range images {
+		response[i] = ProductImageResponse{
+			ID:           image.ID,
+			URL:          image.URL,
+			MediumURL:    image.MediumURL,
+			ThumbnailURL: image.ThumbnailURL,
+			AltText:      image.AltText,
+			Position:     image.Position,
+			IsPrimary:    image.IsPrimary,
+		}
+	}
+
+	return response, nil
+}
+
+// _replacePrimaryImage points the primary gallery image at a new image,
+// creating the gallery when the product has none yet
+func _replacePrimaryImage(tx *gorm.DB, productID uint64, image utils.UploadedImage, altText string) error {
+	var primary models.ProductImage
+	err := tx.Where("product_id = ? AND is_primary = ?", productID, true).First(&primary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		primary = models.ProductImage{ProductID: productID, AltText: altText, IsPrimary: true}
+	} else if err != nil {
+		return err
+	}
+	primary.URL = image.URL
+	primary.MediumURL = image.MediumURL
+	primary.ThumbnailURL = image.ThumbnailURL
+	if err := tx.Save(&primary).Error; err != nil {
+		return err
+	}
+	// The media of the replaced image is left to garbage collection
+	if err := models.ReferenceProductImageMedia(tx, primary.ID, image.Keys); err != nil {
+		return err
+	}
+	return _syncPrimaryImage(tx, productID)
+}
+
+// _setPrimaryImage marks imageID as the only primary image of the product
+func _setPrimaryImage(tx *gorm.DB, productID, imageID uint64) error {
+	if err := tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND id <> ?", productID, imageID).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND id = ?", productID, imageID).
+		Update("is_primary", true).Error
+}
+
+// _syncPrimaryImage makes sure a gallery has exactly one primary image
+// (falling back to the first one by position) and mirrors its URL into
+// products.image_url, which the listing endpoints read.
+func _syncPrimaryImage(tx *gorm.DB, productID uint64) error {
+	var images []models.ProductImage
+	if err := tx.Where("product_id = ?", productID).
+		Order("position ASC, id ASC").
+		Find(&images).Error; err != nil {
+		return err
+	}
+
+	primaryURL := ""
+	if len(images) > 0 {
+		primary := images[0]
+		for _, image := range images {
+			if image.IsPrimary {
+				primary = image
+				break
+			}
+		}
+		if err := _setPrimaryImage(tx, productID, primary.ID); err != nil {
+			return fmt.Errorf("failed to set primary image: %v", err)
+		}
+		primaryURL = primary.URL
+	}
+
+	return tx.Model(&models.Product{}).
+		Where("id = ?", productID).
+		UpdateColumns(map[string]interface{}{
+			"image_url": primaryURL,
+			"version":   gorm.Expr("version + 1"),
+		}).Error
+}
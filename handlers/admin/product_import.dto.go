@@ -0,0 +1,30 @@
+package admin
+
+type ImportRowError struct {
+	Row      int      `json:"row" example:"3"` // Line in the file, the header being line 1
+	SKU      string   `json:"sku" example:"MILK-1L"`
+	Messages []string `json:"messages"`
+}
+
+type ImportDryRunResponse struct {
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	InvalidRows int              `json:"invalid_rows"`
+	Errors      []ImportRowError `json:"errors"`
+}
+
+type ImportJobResponse struct {
+	ID            uint64           `json:"id" example:"1"`
+	FileName      string           `json:"file_name"`
+	Status        string           `json:"status" example:"running"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Progress      float64          `json:"progress" example:"42.5"` // Percentage of processed rows
+	CreatedCount  int              `json:"created_count"`
+	UpdatedCount  int              `json:"updated_count"`
+	FailedCount   int              `json:"failed_count"`
+	Errors        []ImportRowError `json:"errors"`
+	StartedAt     *string          `json:"started_at"`
+	FinishedAt    *string          `json:"finished_at"`
+	CreatedAt     string           `json:"created_at"`
+}
@@ -0,0 +1,503 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"deketna/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxImportRows       = 10000
+	importProgressEvery = 25 // Rows between progress updates of a running job
+)
+
+// productImportRow is a validated row of an import file. Optional cells that
+// were left empty are nil and keep the current value of an existing product.
+type productImportRow struct {
+	Line        int
+	ID          *uint64 // Product to update, matched by SKU when nil
+	SKU         string
+	Name        string
+	Price       float64
+	Stock       int
+	CategoryID  *uint
+	ImageURL    *string
+	Brand       *string
+	Description *string
+}
+
+// @Summary Import products
+// @Description Admin imports products from a CSV, XLSX or JSON file with the columns id, sku, name, price, stock, category, image_url, brand and description, as the product export writes them. Products are matched by id, or by SKU when the id is empty: existing ones are updated, rows matching no product are created as drafts. With dry_run every row is validated and errors are returned by row without writing anything; otherwise the valid rows are imported by a background job whose progress can be polled.
+// @Tags Admin Product
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV, XLSX or JSON file"
+// @Param format query string false "File format (csv, xlsx or json), taken from the file extension by default"
+// @Param dry_run query bool false "Only validate the file"
+// @Success 200 {object} helper.SuccessResponse{data=ImportDryRunResponse} "Dry run result"
+// @Success 202 {object} helper.SuccessResponse{data=ImportJobResponse} "Import job started"
+// @Failure 400 {object} helper.ErrorResponse "Invalid file"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/import [post]
+func ImportProducts(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID, exists := claims["userid"].(float64)
+	if !exists {
+		helper.SendError(c, http.StatusInternalServerError, []string{"User ID not found in claims"})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"A CSV, XLSX or JSON file is required"})
+		return
+	}
+
+	format, err := _productFileFormat(c.Query("format"), fileHeader.Filename)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Failed to open uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	records, err := _readProductFile(file, format)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	}
+	if len(records) == 0 {
+		helper.SendError(c, http.StatusBadRequest, []string{"File has no product rows"})
+		return
+	}
+	if len(records) > maxImportRows {
+		helper.SendError(c, http.StatusBadRequest, []string{fmt.Sprintf("File has %d rows, the limit is %d", len(records), maxImportRows)})
+		return
+	}
+
+	rows, rowErrors, err := _validateImportRecords(config.DB, records)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to validate file"})
+		return
+	}
+
+	if dryRun {
+		helper.SendSuccess(c, http.StatusOK, "Dry run completed", ImportDryRunResponse{
+			TotalRows:   len(records),
+			ValidRows:   len(rows),
+			InvalidRows: len(rowErrors),
+			Errors:      rowErrors,
+		})
+		return
+	}
+
+	// Rows that failed validation are recorded on the job right away
+	encodedErrors, _ := json.Marshal(rowErrors)
+	job := models.ProductImportJob{
+		FileName:      fileHeader.Filename,
+		Status:        models.ImportJobPending,
+		TotalRows:     len(records),
+		ProcessedRows: len(rowErrors),
+		FailedCount:   len(rowErrors),
+		Errors:        string(encodedErrors),
+		CreatedBy:     uint64(adminID),
+	}
+	if err := config.DB.Create(&job).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to create import job"})
+		return
+	}
+
+	go _runProductImport(config.DB, job.ID, uint64(adminID), rows, rowErrors)
+
+	helper.SendSuccess(c, http.StatusAccepted, "Import started", _mapImportJob(job))
+}
+
+// @Summary Get product import job
+// @Description Retrieve the progress and row errors of a product import job
+// @Tags Admin Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param job_id path int true "Import job ID"
+// @Success 200 {object} helper.SuccessResponse{data=ImportJobResponse} "Import job"
+// @Failure 400 {object} helper.ErrorResponse "Invalid job ID"
+// @Failure 404 {object} helper.ErrorResponse "Import job not found"
+// @Router /admin/products/import/{job_id} [get]
+func GetProductImportJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("job_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid job ID"})
+		return
+	}
+
+	var job models.ProductImportJob
+	if err := config.DB.First(&job, jobID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Import job not found"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Import job retrieved successfully", _mapImportJob(job))
+}
+
+// _validateImportRecords checks every row of an import file and resolves
+// categories by name or ID. Rows with errors are left out of the result.
+func _validateImportRecords(db *gorm.DB, records []productFileRecord) ([]productImportRow, []ImportRowError, error) {
+	var categories []models.Category
+	if err := db.Select("id", "name").Find(&categories).Error; err != nil {
+		return nil, nil, err
+	}
+	categoryByName := make(map[string]uint, len(categories))
+	categoryByID := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		categoryByName[strings.ToLower(category.Name)] = category.ID
+		categoryByID[category.ID] = true
+	}
+
+	rows := make([]productImportRow, 0, len(records))
+	rowErrors := []ImportRowError{}
+	seenSKU := make(map[string]int, len(records))
+	seenID := make(map[uint64]int, len(records))
+
+	for _, record := range records {
+		var messages []string
+		row := productImportRow{
+			Line: record.Line,
+			SKU:  record.Get("sku"),
+			Name: record.Get("name"),
+		}
+
+		if value := record.Get("id"); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			switch {
+			case err != nil || id == 0:
+				messages = append(messages, "id must be a whole number greater than 0")
+			case seenID[id] != 0:
+				messages = append(messages, fmt.Sprintf("id %d already appears on row %d", id, seenID[id]))
+			default:
+				seenID[id] = record.Line
+				row.ID = &id
+			}
+		}
+
+		// Products without a SKU are matched by id, or created when it's empty too
+		switch {
+		case row.SKU == "":
+		case len(row.SKU) > 64:
+			messages = append(messages, "sku must be at most 64 characters")
+		case seenSKU[row.SKU] != 0:
+			messages = append(messages, fmt.Sprintf("sku %q already appears on row %d", row.SKU, seenSKU[row.SKU]))
+		default:
+			seenSKU[row.SKU] = record.Line
+		}
+
+		if row.Name == "" {
+			messages = append(messages, "name is required")
+		}
+
+		price, err := strconv.ParseFloat(record.Get("price"), 64)
+		if err != nil || price <= 0 {
+			messages = append(messages, "price must be a number greater than 0")
+		}
+		row.Price = price
+
+		stock, err := strconv.Atoi(record.Get("stock"))
+		if err != nil || stock < 0 {
+			messages = append(messages, "stock must be a whole number of at least 0")
+		}
+		row.Stock = stock
+
+		if category := record.Get("category"); category != "" {
+			if id, err := strconv.ParseUint(category, 10, 64); err == nil && categoryByID[uint(id)] {
+				categoryID := uint(id)
+				row.CategoryID = &categoryID
+			} else if id, ok := categoryByName[strings.ToLower(category)]; ok {
+				row.CategoryID = &id
+			} else {
+				messages = append(messages, fmt.Sprintf("unknown category %q", category))
+			}
+		}
+
+		if imageURL := record.Get("image_url"); imageURL != "" {
+			parsed, err := url.ParseRequestURI(imageURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+				messages = append(messages, "image_url must be an http or https URL")
+			}
+			row.ImageURL = &imageURL
+		}
+
+		if brand := record.Get("brand"); brand != "" {
+			if len(brand) > 255 {
+				messages = append(messages, "brand must be at most 255 characters")
+			}
+			row.Brand = &brand
+		}
+
+		if description := record.Get("description"); description != "" {
+			row.Description = &description
+		}
+
+		if len(messages) > 0 {
+			rowErrors = append(rowErrors, ImportRowError{Row: record.Line, SKU: row.SKU, Messages: messages})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+// _runProductImport upserts validated rows one by one, recording progress and
+// per-row failures on the job as it goes. Jobs that stop saving progress, as
+// when the server restarts halfway, are failed by the import reaper job.
+func _runProductImport(db *gorm.DB, jobID, sellerID uint64, rows []productImportRow, rowErrors []ImportRowError) {
+	startedAt := time.Now()
+	db.Model(&models.ProductImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":     models.ImportJobRunning,
+		"started_at": startedAt,
+	})
+
+	processed := len(rowErrors)
+	failed := len(rowErrors)
+	created, updated := 0, 0
+
+	saveProgress := func(extra map[string]interface{}) error {
+		encodedErrors, _ := json.Marshal(rowErrors)
+		fields := map[string]interface{}{
+			"processed_rows": processed,
+			"created_count":  created,
+			"updated_count":  updated,
+			"failed_count":   failed,
+			"errors":         string(encodedErrors),
+		}
+		for key, value := range extra {
+			fields[key] = value
+		}
+		return db.Model(&models.ProductImportJob{}).Where("id = ?", jobID).Updates(fields).Error
+	}
+
+	for i, row := range rows {
+		var isNew bool
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			isNew, err = _upsertImportedProduct(tx, jobID, row, sellerID)
+			return err
+		})
+
+		processed++
+		switch {
+		case err != nil:
+			failed++
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Line, SKU: row.SKU, Messages: []string{err.Error()}})
+		case isNew:
+			created++
+		default:
+			updated++
+		}
+
+		if (i+1)%importProgressEvery == 0 {
+			if err := saveProgress(nil); err != nil {
+				log.Printf("import job %d: failed to save progress: %v", jobID, err)
+			}
+		}
+	}
+
+	if err := saveProgress(map[string]interface{}{
+		"status":      models.ImportJobCompleted,
+		"finished_at": time.Now(),
+	}); err != nil {
+		log.Printf("import job %d: failed to complete: %v", jobID, err)
+		db.Model(&models.ProductImportJob{}).Where("id = ?", jobID).Update("status", models.ImportJobFailed)
+	}
+}
+
+// _upsertImportedProduct creates or updates the product with the row's id,
+// or SKU when it has none, and reports whether it was created
+func _upsertImportedProduct(tx *gorm.DB, jobID uint64, row productImportRow, sellerID uint64) (bool, error) {
+	// The product stays locked until the row is saved, so edits and sales
+	// made meanwhile are neither overwritten nor miscounted
+	var product models.Product
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Unscoped()
+	var err error
+	switch {
+	case row.ID != nil:
+		err = query.First(&product, *row.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, fmt.Errorf("no product with id %d", *row.ID)
+		}
+	case row.SKU != "":
+		err = query.Where("sku = ?", row.SKU).First(&product).Error
+	default:
+		err = gorm.ErrRecordNotFound
+	}
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return false, errors.New("failed to look up product")
+	}
+	if !isNew && product.DeletedAt.Valid {
+		return false, errors.New("product is archived, restore it before importing")
+	}
+	if !isNew && product.Type == models.ProductTypeBundle {
+		return false, errors.New("product is a bundle, its stock comes from its components")
+	}
+	// A row matched by id may set or change the SKU
+	if !isNew && row.SKU != "" && row.SKU != product.SKU {
+		taken, err := _isSKUTaken(tx, row.SKU, product.ID)
+		if err != nil {
+			return false, errors.New("failed to validate SKU")
+		}
+		if taken {
+			return false, errors.New("SKU is already used by another product")
+		}
+	}
+
+	// New products go through review like any other, as drafts
+	if isNew {
+		product = models.Product{
+			SKU:               row.SKU,
+			SellerID:          sellerID,
+			Status:            models.ProductStatusDraft,
+			DescriptionFormat: helper.DescriptionFormatMarkdown,
+		}
+	}
+
+	previousPrice := product.Price
+	renamed := product.Name != row.Name
+	updates := map[string]interface{}{
+		"name":  row.Name,
+		"price": row.Price,
+	}
+	product.Name = row.Name
+	product.Price = row.Price
+	if row.SKU != "" {
+		product.SKU = row.SKU
+		updates["sku"] = row.SKU
+	}
+	if row.CategoryID != nil {
+		product.CategoryID = row.CategoryID
+		updates["category_id"] = *row.CategoryID
+	}
+	if row.Brand != nil {
+		product.Brand = *row.Brand
+		updates["brand"] = *row.Brand
+	}
+	if row.Description != nil {
+		descriptionHTML, err := helper.RenderDescription(*row.Description, product.DescriptionFormat)
+		if err != nil {
+			return false, err
+		}
+		product.Description = *row.Description
+		product.DescriptionHTML = descriptionHTML
+		updates["description"] = *row.Description
+		updates["description_html"] = descriptionHTML
+	}
+
+	if isNew {
+		if err := tx.Omit(clause.Associations).Create(&product).Error; err != nil {
+			return false, errors.New("failed to save product")
+		}
+	} else {
+		// Only the imported columns are written, the rest may have moved on
+		// through sales, reviews and the gallery
+		updates["version"] = gorm.Expr("version + 1")
+		if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Updates(updates).Error; err != nil {
+			return false, errors.New("failed to save product")
+		}
+		product.Version++
+	}
+
+	// The file holds the counted stock, the ledger records the difference
+	// from the stock of the locked product
+	reason := models.StockReasonAdjustment
+	if isNew {
+		reason = models.StockReasonRestock
+	}
+	if err := models.ApplyStockMovement(tx, &models.StockMovement{
+		ProductID:   product.ID,
+		Delta:       row.Stock - product.Stock,
+		Reason:      reason,
+		ReferenceID: fmt.Sprintf("import:%d", jobID),
+		ActorID:     &sellerID,
+	}); err != nil {
+		return false, errors.New("failed to record stock movement")
+	}
+	if err := models.RecordPriceChange(tx, product.ID, previousPrice, product.Price, &sellerID); err != nil {
+		return false, errors.New("failed to record price change")
+	}
+	if renamed {
+		if err := models.AssignProductSlug(tx, &product); err != nil {
+			return false, errors.New("failed to assign product slug")
+		}
+	}
+
+	if row.ImageURL != nil && *row.ImageURL != product.ImageURL {
+		// Imported images are external, so the same URL serves every size
+		image := utils.UploadedImage{URL: *row.ImageURL, MediumURL: *row.ImageURL, ThumbnailURL: *row.ImageURL}
+		if err := _replacePrimaryImage(tx, product.ID, image, product.Name); err != nil {
+			return false, errors.New("failed to save product image")
+		}
+	}
+
+	if err := models.RecordProductRevision(tx, product.ID, models.RevisionImported, &sellerID, nil); err != nil {
+		return false, errors.New("failed to record product revision")
+	}
+
+	return isNew, nil
+}
+
+func _mapImportJob(job models.ProductImportJob) ImportJobResponse {
+	rowErrors := []ImportRowError{}
+	if job.Errors != "" {
+		json.Unmarshal([]byte(job.Errors), &rowErrors)
+	}
+
+	progress := 100.0
+	if job.TotalRows > 0 {
+		progress = float64(job.ProcessedRows) * 100 / float64(job.TotalRows)
+	}
+
+	response := ImportJobResponse{
+		ID:            job.ID,
+		FileName:      job.FileName,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Progress:      progress,
+		CreatedCount:  job.CreatedCount,
+		UpdatedCount:  job.UpdatedCount,
+		FailedCount:   job.FailedCount,
+		Errors:        rowErrors,
+		CreatedAt:     job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.StartedAt != nil {
+		startedAt := job.StartedAt.Format(time.RFC3339)
+		response.StartedAt = &startedAt
+	}
+	if job.FinishedAt != nil {
+		finishedAt := job.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+	return response
+}
@@ -0,0 +1,21 @@
+package admin
+
+type AdminReviewResponse struct {
+	ID           uint64  `json:"id"`
+	ProductID    uint64  `json:"product_id"`
+	ProductName  string  `json:"product_name"`
+	BuyerID      uint64  `json:"buyer_id"`
+	BuyerName    string  `json:"buyer_name"`
+	OrderID      uint64  `json:"order_id"`
+	Rating       int     `json:"rating"`
+	Body         string  `json:"body"`
+	HelpfulCount int     `json:"helpful_count"`
+	IsHidden     bool    `json:"is_hidden"`
+	HiddenReason string  `json:"hidden_reason"`
+	HiddenAt     *string `json:"hidden_at"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type HideReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"Contains personal information"`
+}
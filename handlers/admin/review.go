@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Get reviews
+// @Description Admin lists product reviews, including hidden ones, for moderation
+// @Tags Admin Review
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 10)"
+// @Param product_id query int false "Only reviews of this product"
+// @Param hidden query bool false "Filter on hidden state"
+// @Success 200 {object} helper.PaginationResponse{data=[]AdminReviewResponse} "List of reviews"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/reviews [get]
+func GetReviews(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Table("product_reviews")
+	if productID, err := strconv.ParseUint(c.Query("product_id"), 10, 64); err == nil {
+		query = query.Where("product_reviews.product_id = ?", productID)
+	}
+	if hidden, err := strconv.ParseBool(c.Query("hidden")); err == nil {
+		query = query.Where("product_reviews.is_hidden = ?", hidden)
+	}
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count reviews"})
+		return
+	}
+
+	var reviews []AdminReviewResponse
+	err = query.
+		Select(`
+			product_reviews.id,
+			product_reviews.product_id,
+			products.name AS product_name,
+			product_reviews.buyer_id,
+			COALESCE(profiles.name, '') AS buyer_name,
+			product_reviews.order_id,
+			product_reviews.rating,
+			product_reviews.body,
+			product_reviews.helpful_count,
+			product_reviews.is_hidden,
+			product_reviews.hidden_reason,
+			product_reviews.hidden_at,
+			product_reviews.created_at`).
+		Joins("JOIN products ON products.id = product_reviews.product_id").
+		Joins("LEFT JOIN profiles ON profiles.user_id = product_reviews.buyer_id").
+		Order("product_reviews.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&reviews).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve reviews"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Reviews retrieved successfully", reviews, pagination)
+}
+
+// @Summary Hide review
+// @Description Admin hides a review from the storefront. Hidden reviews no longer count towards the product rating.
+// @Tags Admin Review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param request body HideReviewRequest true "Moderation reason"
+// @Success 200 {object} helper.SuccessResponse "Review hidden successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 404 {object} helper.ErrorResponse "Review not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/review/{id}/hide [put]
+func HideReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid review ID"})
+		return
+	}
+
+	var req HideReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input", err.Error()})
+		return
+	}
+
+	now := time.Now()
+	err = _setReviewVisibility(reviewID, map[string]interface{}{
+		"is_hidden":     true,
+		"hidden_reason": req.Reason,
+		"hidden_at":     &now,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Review not found"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to hide review"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Review hidden successfully", nil)
+}
+
+// @Summary Unhide review
+// @Description Admin makes a hidden review visible again
+// @Tags Admin Review
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} helper.SuccessResponse "Review restored successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid review ID"
+// @Failure 404 {object} helper.ErrorResponse "Review not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/review/{id}/unhide [put]
+func UnhideReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid review ID"})
+		return
+	}
+
+	err = _setReviewVisibility(reviewID, map[string]interface{}{
+		"is_hidden":     false,
+		"hidden_reason": "",
+		"hidden_at":     nil,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Review not found"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to restore review"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Review restored successfully", nil)
+}
+
+// _setReviewVisibility updates the moderation fields of a review and refreshes
+// the product rating in the same transaction
+func _setReviewVisibility(reviewID uint64, updates map[string]interface{}) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var review models.ProductReview
+		if err := tx.Select("id", "product_id").First(&review, reviewID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&review).Updates(updates).Error; err != nil {
+			return err
+		}
+		return models.RefreshProductRating(tx, review.ProductID)
+	})
+}
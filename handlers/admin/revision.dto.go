@@ -0,0 +1,20 @@
+package admin
+
+import "deketna/models"
+
+type RevisionChange struct {
+	Field string      `json:"field" example:"price"` // Attributes appear as attributes.<key>
+	Old   interface{} `json:"old"`                   // Null when the field was not set before
+	New   interface{} `json:"new"`
+}
+
+type ProductRevisionResponse struct {
+	Revision     int                    `json:"revision" example:"3"`
+	Action       string                 `json:"action" example:"updated"` // baseline, created, updated, imported, archived, unarchived or rolled_back
+	ActorID      *uint64                `json:"actor_id"`
+	ActorEmail   string                 `json:"actor_email"`
+	RestoredFrom *int                   `json:"restored_from,omitempty"` // Revision a rollback went back to
+	CreatedAt    string                 `json:"created_at"`
+	Changes      []RevisionChange       `json:"changes"` // Compared to the previous revision
+	Snapshot     models.ProductSnapshot `json:"snapshot"`
+}
@@ -0,0 +1,281 @@
+package admin
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// productRevisionRow is a revision as read for the history
+type productRevisionRow struct {
+	Revision     int
+	Action       string
+	Snapshot     string
+	ActorID      *uint64
+	ActorEmail   string
+	RestoredFrom *int
+	CreatedAt    time.Time
+}
+
+// @Summary Get product revisions
+// @Description Admin lists the revisions of a product, newest first, each with the fields it changed compared to the revision before it
+// @Tags Admin Product
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]ProductRevisionResponse} "Product revisions"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/revisions [get]
+func GetProductRevisions(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Table("product_revisions").Where("product_revisions.product_id = ?", productID)
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to count product revisions"})
+		return
+	}
+
+	// One revision more than the page holds, the one the oldest on the page is compared to
+	var rows []productRevisionRow
+	err = query.
+		Select(`
+			product_revisions.revision,
+			product_revisions.action,
+			product_revisions.snapshot,
+			product_revisions.actor_id,
+			COALESCE(users.email, '') AS actor_email,
+			product_revisions.restored_from,
+			product_revisions.created_at`).
+		Joins("LEFT JOIN users ON users.id = product_revisions.actor_id").
+		Order("product_revisions.revision DESC").
+		Limit(limit + 1).
+		Offset(offset).
+		Scan(&rows).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve product revisions"})
+		return
+	}
+
+	revisions := []ProductRevisionResponse{}
+	for i, row := range rows {
+		if i == limit {
+			break
+		}
+
+		var snapshot models.ProductSnapshot
+		if err := json.Unmarshal([]byte(row.Snapshot), &snapshot); err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to read product revision"})
+			return
+		}
+		var previous *models.ProductSnapshot
+		if i+1 < len(rows) {
+			previous = &models.ProductSnapshot{}
+			if err := json.Unmarshal([]byte(rows[i+1].Snapshot), previous); err != nil {
+				helper.SendError(c, http.StatusInternalServerError, []string{"Failed to read product revision"})
+				return
+			}
+		}
+
+		revisions = append(revisions, ProductRevisionResponse{
+			Revision:     row.Revision,
+			Action:       row.Action,
+			ActorID:      row.ActorID,
+			ActorEmail:   row.ActorEmail,
+			RestoredFrom: row.RestoredFrom,
+			CreatedAt:    row.CreatedAt.Format(time.RFC3339),
+			Changes:      _diffSnapshots(previous, snapshot),
+			Snapshot:     snapshot,
+		})
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Product revisions retrieved successfully", revisions, pagination)
+}
+
+// @Summary Restore a product revision
+// @Description Admin rolls a product back to the fields of one of its revisions. Stock, the gallery and bundle components are left as they are. The rollback is recorded as a new revision.
+// @Tags Admin Product
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param rev path int true "Revision number"
+// @Param If-Match header string false "ETag of the product, the rollback is refused when the product changed since"
+// @Success 200 {object} helper.SuccessResponse{data=GetProductResponse} "Product restored to the revision"
+// @Header 200 {string} ETag "New version of the product"
+// @Failure 400 {object} helper.ErrorResponse "The revision can't be applied any more"
+// @Failure 404 {object} helper.ErrorResponse "Product or revision not found"
+// @Failure 412 {object} helper.ErrorResponse "Product was changed since it was read"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/products/{id}/revisions/{rev}/restore [post]
+func RestoreProductRevision(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+	revisionNumber, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid revision"})
+		return
+	}
+
+	var revision models.ProductRevision
+	if err := config.DB.Where("product_id = ? AND revision = ?", productID, revisionNumber).First(&revision).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Revision not found"})
+		return
+	}
+	var snapshot models.ProductSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to read product revision"})
+		return
+	}
+
+	// Without If-Match the rollback applies to whatever the product is now
+	var current models.Product
+	if err := config.DB.Select("id", "version").First(&current, productID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+	version := current.Version
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		var ok bool
+		if version, ok = _parseProductETag(ifMatch); !ok {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid If-Match header"})
+			return
+		}
+	}
+
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	product, err := _editProduct(config.DB, productID, uint64(claims["userid"].(float64)), version, _snapshotEditRequest(snapshot), &revisionNumber)
+	var vErr *validationError
+	switch {
+	case errors.As(err, &vErr):
+		helper.SendError(c, http.StatusBadRequest, vErr.messages)
+		return
+	case errors.Is(err, errProductVersionConflict):
+		helper.SendError(c, http.StatusPreconditionFailed, []string{"Product was changed by someone else, reload it and try again"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to restore product revision"})
+		return
+	}
+
+	c.Header("ETag", _productETag(product.Version))
+	helper.SendSuccess(c, http.StatusOK, "Product restored to revision "+strconv.Itoa(revisionNumber), product)
+}
+
+// _snapshotEditRequest turns a snapshot into the edit that brings a product back to it
+func _snapshotEditRequest(snapshot models.ProductSnapshot) ProductEditRequest {
+	req := ProductEditRequest{
+		SKU:               &snapshot.SKU,
+		Name:              snapshot.Name,
+		Price:             &snapshot.Price,
+		CategoryID:        snapshot.CategoryID,
+		Description:       &snapshot.Description,
+		DescriptionFormat: &snapshot.DescriptionFormat,
+		Brand:             &snapshot.Brand,
+		WeightGrams:       &snapshot.WeightGrams,
+		LengthCM:          &snapshot.LengthCM,
+		WidthCM:           &snapshot.WidthCM,
+		HeightCM:          &snapshot.HeightCM,
+		Attributes:        snapshot.Attributes,
+		ReorderThreshold:  &snapshot.ReorderThreshold,
+	}
+	if req.Attributes == nil {
+		req.Attributes = map[string]string{}
+	}
+
+	// Archiving has its own endpoints, a rollback keeps the product where it is
+	if snapshot.Status != models.ProductStatusArchived {
+		req.Status = &snapshot.Status
+		if snapshot.Status == models.ProductStatusScheduled {
+			req.PublishAt = snapshot.PublishAt
+		}
+	}
+	return req
+}
+
+// _diffSnapshots lists the fields that differ between two snapshots, every
+// set field when there is no previous snapshot
+func _diffSnapshots(previous *models.ProductSnapshot, current models.ProductSnapshot) []RevisionChange {
+	before := map[string]interface{}{}
+	if previous != nil {
+		before = _flattenSnapshot(*previous)
+	}
+	after := _flattenSnapshot(current)
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []RevisionChange{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, RevisionChange{Field: field, Old: before[field], New: after[field]})
+		}
+	}
+	return changes
+}
+
+// _flattenSnapshot maps a snapshot by JSON field name, attributes as attributes.<key>
+func _flattenSnapshot(snapshot models.ProductSnapshot) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, _ := json.Marshal(snapshot)
+	json.Unmarshal(data, &fields)
+
+	delete(fields, "attributes")
+	for key, value := range snapshot.Attributes {
+		fields["attributes."+key] = value
+	}
+	return fields
+}
@@ -0,0 +1,22 @@
+package admin
+
+type CreateUploadRequest struct {
+	Filename    string `json:"filename" binding:"required,max=255" example:"photo.jpg"`
+	ContentType string `json:"content_type" binding:"required" example:"image/jpeg"`
+}
+
+type CreateUploadResponse struct {
+	UploadID  uint64            `json:"upload_id" example:"1"`
+	UploadURL string            `json:"upload_url"` // Signed URL to PUT the file to
+	Method    string            `json:"method" example:"PUT"`
+	Headers   map[string]string `json:"headers"` // Headers to send with the file
+	ExpiresAt string            `json:"expires_at"`
+}
+
+type UploadResponse struct {
+	UploadID     uint64 `json:"upload_id" example:"1"`
+	Status       string `json:"status" example:"confirmed"`
+	URL          string `json:"url,omitempty"` // Large rendition, once confirmed
+	MediumURL    string `json:"medium_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
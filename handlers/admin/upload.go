@@ -0,0 +1,221 @@
+package admin
+
+import (
+	"crypto/rand"
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"deketna/storage"
+	"deketna/utils"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// confirmedUploadHold is how long products can use a confirmed upload. Its
+// renditions are kept meanwhile, even before any product references them.
+const confirmedUploadHold = 24 * time.Hour
+
+// errUploadGone is returned for uploads products can no longer use
+var errUploadGone = errors.New("upload can no longer be used")
+
+// @Summary Start a direct upload
+// @Description Admin gets a signed URL to PUT an image straight to storage, bypassing the API. The upload has to be confirmed before products can use it.
+// @Tags Admin Upload
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateUploadRequest true "File to upload"
+// @Success 201 {object} helper.SuccessResponse{data=CreateUploadResponse} "Upload URL"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/uploads [post]
+func CreateUpload(c *gin.Context) {
+	var req CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input", err.Error()})
+		return
+	}
+	if !utils.IsAllowedImageType(req.ContentType) {
+		helper.SendError(c, http.StatusBadRequest, []string{"content_type must be image/jpeg, image/png, image/gif or image/webp"})
+		return
+	}
+
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	adminID := uint64(claims["userid"].(float64))
+
+	// Staging keys are random so an upload URL can't be aimed at another file
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to start upload"})
+		return
+	}
+	stagingKey := "staging/" + hex.EncodeToString(token)
+
+	ttl := config.UploadURLTTL()
+	uploadURL, err := storage.SignedUploadURL(stagingKey, ttl)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to sign upload URL"})
+		return
+	}
+
+	upload := models.Upload{
+		StagingKey:       stagingKey,
+		OriginalFilename: filepath.Base(req.Filename),
+		ContentType:      req.ContentType,
+		Status:           models.UploadPending,
+		CreatedBy:        adminID,
+		ExpiresAt:        time.Now().Add(ttl),
+	}
+	if err := config.DB.Create(&upload).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to start upload"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Upload started successfully", CreateUploadResponse{
+		UploadID:  upload.ID,
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": upload.ContentType},
+		ExpiresAt: upload.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// @Summary Confirm a direct upload
+// @Description Admin confirms that the file was stored through the upload URL. The file is checked to be a valid image and turned into renditions, after which products can use the upload by its ID for 24 hours. Confirming twice returns the same renditions.
+// @Tags Admin Upload
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Upload ID"
+// @Success 200 {object} helper.SuccessResponse{data=UploadResponse} "Upload confirmed"
+// @Failure 400 {object} helper.ErrorResponse "Not a valid image"
+// @Failure 404 {object} helper.ErrorResponse "Upload not found"
+// @Failure 409 {object} helper.ErrorResponse "File not stored yet"
+// @Failure 410 {object} helper.ErrorResponse "Upload expired or rejected"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /admin/uploads/{id}/confirm [post]
+func ConfirmUpload(c *gin.Context) {
+	uploadID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid upload ID"})
+		return
+	}
+
+	var upload models.Upload
+	if err := config.DB.First(&upload, uploadID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Upload not found"})
+		return
+	}
+	switch upload.Status {
+	case models.UploadConfirmed:
+		helper.SendSuccess(c, http.StatusOK, "Upload confirmed successfully", _mapUpload(upload))
+		return
+	case models.UploadRejected, models.UploadExpired:
+		helper.SendError(c, http.StatusGone, []string{fmt.Sprintf("Upload was %s, start a new one", upload.Status)})
+		return
+	}
+
+	body, err := storage.Get(upload.StagingKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		helper.SendError(c, http.StatusConflict, []string{"The file has not been uploaded yet"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to read uploaded file"})
+		return
+	}
+	image, err := utils.StoreImage(body, upload.OriginalFilename)
+	body.Close()
+
+	var invalid bool
+	if errors.Is(err, utils.ErrInvalidImage) {
+		invalid = true
+		upload.Status = models.UploadRejected
+	} else if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{err.Error()})
+		return
+	} else {
+		now := time.Now()
+		upload.Status = models.UploadConfirmed
+		upload.SourceHash = image.SourceHash
+		upload.ConfirmedAt = &now
+		upload.ExpiresAt = now.Add(confirmedUploadHold)
+	}
+
+	// Renditions are stored under their own keys, the staging file has served its purpose
+	if err := storage.Delete(upload.StagingKey); err != nil {
+		log.Printf("upload %d: failed to delete staging file: %v", upload.ID, err)
+	}
+
+	result := config.DB.Model(&upload).
+		Where("status = ?", models.UploadPending).
+		Select("Status", "SourceHash", "ConfirmedAt", "ExpiresAt").
+		Updates(&upload)
+	if result.Error != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to confirm upload"})
+		return
+	}
+	if invalid {
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Upload confirmed successfully", _mapUpload(upload))
+}
+
+// _uploadedImage is the image of a confirmed upload, for products to use. It
+// fails with errUploadGone once the upload expired or its renditions were
+// collected.
+func _uploadedImage(db *gorm.DB, uploadID uint64) (utils.UploadedImage, error) {
+	var upload models.Upload
+	err := db.First(&upload, uploadID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.UploadedImage{}, &validationError{messages: []string{fmt.Sprintf("upload %d not found", uploadID)}}
+	}
+	if err != nil {
+		return utils.UploadedImage{}, err
+	}
+
+	switch {
+	case upload.Status == models.UploadExpired || upload.Status == models.UploadRejected:
+		return utils.UploadedImage{}, fmt.Errorf("%w: upload %d was %s, start a new one", errUploadGone, uploadID, upload.Status)
+	case upload.Status != models.UploadConfirmed:
+		return utils.UploadedImage{}, &validationError{messages: []string{fmt.Sprintf("upload %d is not a confirmed upload", uploadID)}}
+	case upload.ExpiresAt.Before(time.Now()):
+		return utils.UploadedImage{}, fmt.Errorf("%w: upload %d expired, start a new one", errUploadGone, uploadID)
+	}
+
+	// Claiming the renditions keeps garbage collection away from them
+	image := utils.StoredImage(upload.SourceHash)
+	stored, err := models.ReuseMedia(db, image.Keys)
+	if err != nil {
+		return utils.UploadedImage{}, err
+	}
+	if !stored {
+		return utils.UploadedImage{}, fmt.Errorf("%w: the images of upload %d are no longer stored, start a new one", errUploadGone, uploadID)
+	}
+	return image, nil
+}
+
+func _mapUpload(upload models.Upload) UploadResponse {
+	response := UploadResponse{
+		UploadID: upload.ID,
+		Status:   upload.Status,
+	}
+	if upload.Status == models.UploadConfirmed {
+		image := utils.StoredImage(upload.SourceHash)
+		response.URL = image.URL
+		response.MediumURL = image.MediumURL
+		response.ThumbnailURL = image.ThumbnailURL
+	}
+	return response
+}
@@ -9,6 +9,8 @@ type CartItemResponse struct {
 	Price       float64 `json:"price"`
 	Quantity    int     `json:"quantity"`
 	TotalPrice  float64 `json:"total_price"`
+
+	CompareAtPrice *float64 `json:"compare_at_price"` // Regular price while a sale is running
 }
 
 type AddToCartRequest struct {
@@ -24,3 +26,35 @@ type UpdateCartRequest struct {
 	CartItemID uint64 `json:"cart_item_id"`
 	Quantity   int    `json:"quantity"`
 }
+
+// CartWarning flags a cart line the buyer should look at before ordering
+type CartWarning struct {
+	Code    string `json:"code" example:"insufficient_stock"` // insufficient_stock, price_changed, product_archived or product_unavailable
+	Message string `json:"message"`
+}
+
+// CartSummaryItem is a cart line priced at the price in effect now
+type CartSummaryItem struct {
+	CartItemID  uint64        `json:"cart_item_id,omitempty"`
+	ProductID   uint64        `json:"product_id"`
+	ProductName string        `json:"product_name"`
+	Quantity    int           `json:"quantity"`
+	Price       float64       `json:"price"`    // Per unit, a running sale included
+	Subtotal    float64       `json:"subtotal"` // At the regular price
+	Discount    float64       `json:"discount"` // Taken off by a running sale
+	Total       float64       `json:"total"`
+	Warnings    []CartWarning `json:"warnings"`
+
+	CompareAtPrice *float64 `json:"compare_at_price"` // Regular price while a sale is running
+}
+
+// CartSummaryResponse totals what ordering the cart costs. Lines that can't be
+// ordered, archived or unavailable products, are left out of the totals.
+type CartSummaryResponse struct {
+	Items      []CartSummaryItem `json:"items"`
+	Subtotal   float64           `json:"subtotal"`
+	Discount   float64           `json:"discount"`
+	Shipping   float64           `json:"shipping"` // Estimate, by weight unless the order ships free
+	Tax        float64           `json:"tax"`
+	GrandTotal float64           `json:"grand_total"`
+}
@@ -4,11 +4,13 @@ import (
 	"deketna/config"
 	"deketna/helper"
 	"deketna/models"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // AddToCartHandler handles adding goods to the cart
@@ -39,39 +41,29 @@ func AddToCart(c *gin.Context) {
 
 	// Check if product exists
 	var product models.Product
-	if err := config.DB.First(&product, req.ProductID).Error; err != nil {
+	if err := config.DB.Scopes(models.PublicProducts).First(&product, req.ProductID).Error; err != nil {
 		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
 		return
 	}
 
-	// Check if the buyer already has a cart
-	var cart models.Cart
-	if err := config.DB.Where("buyer_id = ?", buyerID).First(&cart).Error; err != nil {
-		// If no cart exists, create one
-		cart = models.Cart{BuyerID: buyerID}
-		config.DB.Create(&cart)
-	}
-
-	// Check if the product already exists in the cart
-	var cartItem models.CartItem
-	if err := config.DB.Where("cart_id = ? AND product_id = ?", cart.ID, req.ProductID).First(&cartItem).Error; err == nil {
-		// If product exists, update the quantity
-		cartItem.Quantity += req.Quantity
-		config.DB.Save(&cartItem)
-	} else {
-		// Add new cart item
-		cartItem = models.CartItem{
-			CartID:    cart.ID,
-			ProductID: req.ProductID,
-			Quantity:  req.Quantity,
+	// Add the product to the buyer's cart, creating the cart on first use
+	var cartItem *models.CartItem
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		cart, err := _getOrCreateCart(tx, buyerID)
+		if err != nil {
+			return err
 		}
-		config.DB.Create(&cartItem)
+		cartItem, err = _addCartItem(tx, cart.ID, product, req.Quantity)
+		return err
+	})
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to add product to cart"})
+		return
 	}
 
-	// Return success response
 	// Return success response
 	helper.SendSuccess(c, http.StatusOK, "Product added to cart successfully", gin.H{
-		"cart_id":      cart.ID,
+		"cart_id":      cartItem.CartID,
 		"cart_item_id": cartItem.ID,
 		"product":      product.Name,
 		"quantity":     req.Quantity,
@@ -80,7 +72,7 @@ func AddToCart(c *gin.Context) {
 
 // GetCarts retrieves all cart items for the logged-in user
 // @Summary Get Cart Items
-// @Description Retrieve all cart items for the logged-in user
+// @Description Retrieve all cart items for the logged-in user, priced at the price in effect now
 // @Tags  Cart
 // @Security BearerAuth
 // @Accept json
@@ -95,6 +87,94 @@ func GetCarts(c *gin.Context) {
 	claims := c.MustGet("claims").(jwt.MapClaims)
 
 	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	cartID, err := _buyerCartID(config.DB, buyerID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve cart items"})
+		return
+	}
+
+	_sendCartItems(c, cartID)
+}
+
+// GetCartSummary prices the buyer's cart
+// @Summary Get Cart Summary
+// @Description Price the buyer's cart as placing the order would: subtotal, sale discounts, shipping estimate, tax and grand total, with warnings for lines short of stock, with a changed price or with a product no longer sold
+// @Tags  Cart
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helper.SuccessResponse{data=CartSummaryResponse} "Cart summary"
+// @Failure 500 {object} helper.ErrorResponse "Failed to price cart"
+// @Router /cart/summary [get]
+func GetCartSummary(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	cartID, err := _buyerCartID(config.DB, buyerID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to price cart"})
+		return
+	}
+
+	_sendCartSummary(c, cartID, buyerID)
+}
+
+// DeleteCart deletes one or more items from the cart
+// @Summary Delete Cart Items
+// @Description Delete one or more items from the user's cart
+// @Tags  Cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body DeleteCartRequest true "Cart Item IDs to delete"
+// @Success 200 {object} helper.SuccessResponse "Cart items deleted successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 500 {object} helper.ErrorResponse "Failed to delete cart items"
+// @Router /cart [delete]
+func DeleteCart(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	cartID, err := _buyerCartID(config.DB, buyerID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to delete cart items"})
+		return
+	}
+
+	_deleteCartItems(c, cartID)
+}
+
+// UpdateCart updates the quantity of a cart item
+// @Summary Update Cart Item
+// @Description Update the quantity of a specific cart item
+// @Tags  Cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body UpdateCartRequest true "Cart item details"
+// @Success 200 {object} helper.SuccessResponse "Cart item updated successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 500 {object} helper.ErrorResponse "Failed to update cart item"
+// @Router /cart [put]
+func UpdateCart(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	cartID, err := _buyerCartID(config.DB, buyerID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update cart item"})
+		return
+	}
+
+	_updateCartItem(c, cartID)
+}
+
+// _sendCartItems responds with a page of the items in a cart, priced at the
+// price in effect now. A cart ID of 0 is an empty cart.
+func _sendCartItems(c *gin.Context, cartID uint64) {
 	// Pagination parameters
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "25")
@@ -120,11 +200,12 @@ func GetCarts(c *gin.Context) {
 			cart_items.quantity,
 
 			products.name AS product_name,
-			products.price, products.image_url,
-			(products.price * cart_items.quantity) AS total_price`).
-		Joins("JOIN carts ON carts.id = cart_items.cart_id").
+			`+models.EffectivePriceSQL+` AS price, products.image_url,
+			`+models.CompareAtPriceSQL+` AS compare_at_price,
+			(`+models.EffectivePriceSQL+` * cart_items.quantity) AS total_price`).
 		Joins("JOIN products ON products.id = cart_items.product_id").
-		Where("carts.buyer_id = ?", buyerID).
+		Joins(models.ActiveSaleJoin).
+		Where("cart_items.cart_id = ?", cartID).
 		Order("cart_items.updated_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -135,7 +216,7 @@ func GetCarts(c *gin.Context) {
 		return
 	}
 
-	config.DB.Model(&models.Cart{}).Where("buyer_id = ?", buyerID).Count(&totalItems)
+	config.DB.Model(&models.CartItem{}).Where("cart_id = ?", cartID).Count(&totalItems)
 
 	// Build pagination metadata
 	totalPages := (int(totalItems) + limit - 1) / limit
@@ -151,23 +232,26 @@ func GetCarts(c *gin.Context) {
 	helper.SendPagination(c, http.StatusOK, "Cart items retrieved successfully", cartItems, pagination)
 }
 
-// DeleteCart deletes one or more items from the cart
-// @Summary Delete Cart Items
-// @Description Delete one or more items from the user's cart
-// @Tags  Cart
-// @Security BearerAuth
-// @Accept json
-// @Produce json
-// @Param payload body DeleteCartRequest true "Cart Item IDs to delete"
-// @Success 200 {object} helper.SuccessResponse "Cart items deleted successfully"
-// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
-// @Failure 500 {object} helper.ErrorResponse "Failed to delete cart items"
-// @Router /cart [delete]
-func DeleteCart(c *gin.Context) {
-	claims := c.MustGet("claims").(jwt.MapClaims)
+// _sendCartSummary responds with the priced items and totals of a cart.
+// Stock reserved by buyerID's own checkout counts as available.
+func _sendCartSummary(c *gin.Context, cartID, buyerID uint64) {
+	lines, err := _cartLines(config.DB, cartID)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to price cart"})
+		return
+	}
 
-	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+	priced, err := _priceCart(config.DB, lines, buyerID, false)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to price cart"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Cart summary retrieved successfully", priced.summary)
+}
 
+// _deleteCartItems deletes the requested items that belong to a cart
+func _deleteCartItems(c *gin.Context, cartID uint64) {
 	var req DeleteCartRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil || len(req.CartItemIDs) == 0 {
@@ -175,7 +259,7 @@ func DeleteCart(c *gin.Context) {
 		return
 	}
 
-	err := config.DB.Where("id IN ? AND cart_id IN (SELECT id FROM carts WHERE buyer_id = ?)", req.CartItemIDs, buyerID).
+	err := config.DB.Where("id IN ? AND cart_id = ?", req.CartItemIDs, cartID).
 		Delete(&models.CartItem{}).Error
 
 	if err != nil {
@@ -186,22 +270,8 @@ func DeleteCart(c *gin.Context) {
 	helper.SendSuccess(c, http.StatusOK, "Cart items deleted successfully", nil)
 }
 
-// UpdateCart updates the quantity of a cart item
-// @Summary Update Cart Item
-// @Description Update the quantity of a specific cart item
-// @Tags  Cart
-// @Security BearerAuth
-// @Accept json
-// @Produce json
-// @Param payload body UpdateCartRequest true "Cart item details"
-// @Success 200 {object} helper.SuccessResponse "Cart item updated successfully"
-// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
-// @Failure 500 {object} helper.ErrorResponse "Failed to update cart item"
-// @Router /cart [put]
-func UpdateCart(c *gin.Context) {
-	claims := c.MustGet("claims").(jwt.MapClaims)
-
-	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+// _updateCartItem sets the quantity of an item that belongs to a cart
+func _updateCartItem(c *gin.Context, cartID uint64) {
 	var req UpdateCartRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity < 1 {
@@ -209,32 +279,74 @@ func UpdateCart(c *gin.Context) {
 		return
 	}
 
-	// Verify if the cart item belongs to the user
-	var exists bool
-	err := config.DB.
-		Table("cart_items").
-		Joins("JOIN carts ON carts.id = cart_items.cart_id").
-		Where("cart_items.id = ? AND carts.buyer_id = ?", req.CartItemID, buyerID).
-		Select("1").
-		Scan(&exists).Error
+	// Only items of this cart are updated
+	result := config.DB.Model(&models.CartItem{}).
+		Where("id = ? AND cart_id = ?", req.CartItemID, cartID).
+		Update("quantity", req.Quantity)
 
-	if err != nil {
+	if result.Error != nil {
 		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update cart item"})
 		return
 	}
-	if !exists {
+	if result.RowsAffected == 0 {
 		helper.SendError(c, http.StatusUnauthorized, []string{"Unauthorized access to cart item"})
 		return
 	}
 
-	err = config.DB.Model(&models.CartItem{}).
-		Where("id = ?", req.CartItemID).
-		Update("quantity", req.Quantity).Error
+	helper.SendSuccess(c, http.StatusOK, "Cart item updated successfully", nil)
+}
+
+// _addCartItem adds quantity of a product to a cart, on top of what the cart
+// already holds. New items remember the price in effect now.
+func _addCartItem(tx *gorm.DB, cartID uint64, product models.Product, quantity int) (*models.CartItem, error) {
+	var cartItem models.CartItem
+	err := tx.Where("cart_id = ? AND product_id = ?", cartID, product.ID).First(&cartItem).Error
+	if err == nil {
+		cartItem.Quantity += quantity
+		if err := tx.Save(&cartItem).Error; err != nil {
+			return nil, err
+		}
+		return &cartItem, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
 
+	price, err := models.EffectivePrice(tx, product)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to update cart item"})
-		return
+		return nil, err
 	}
+	cartItem = models.CartItem{CartID: cartID, ProductID: product.ID, Quantity: quantity, UnitPrice: price}
+	if err := tx.Create(&cartItem).Error; err != nil {
+		return nil, err
+	}
+	return &cartItem, nil
+}
 
-	helper.SendSuccess(c, http.StatusOK, "Cart item updated successfully", nil)
+// _buyerCartID returns the ID of the buyer's cart, 0 when the buyer has none yet
+func _buyerCartID(tx *gorm.DB, buyerID uint64) (uint64, error) {
+	var cart models.Cart
+	err := tx.Select("id").Where("buyer_id = ?", buyerID).First(&cart).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return cart.ID, err
+}
+
+// _getOrCreateCart returns the buyer's cart, creating it on first use
+func _getOrCreateCart(tx *gorm.DB, buyerID uint64) (*models.Cart, error) {
+	var cart models.Cart
+	err := tx.Where("buyer_id = ?", buyerID).First(&cart).Error
+	if err == nil {
+		return &cart, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	cart = models.Cart{BuyerID: &buyerID}
+	if err := tx.Create(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
 }
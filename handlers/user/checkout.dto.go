@@ -0,0 +1,15 @@
+package user
+
+type ReservationResponse struct {
+	ProductID   uint64  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+type CheckoutResponse struct {
+	ExpiresAt string                `json:"expires_at"` // Reserved stock is released after this time
+	Items     []ReservationResponse `json:"items"`
+
+	Summary CartSummaryResponse `json:"summary"` // What placing the order costs
+}
@@ -0,0 +1,153 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// errStockUnavailable carries the per-product reasons a checkout can't reserve stock
+type errStockUnavailable struct {
+	messages []string
+}
+
+func (e *errStockUnavailable) Error() string {
+	return strings.Join(e.messages, "; ")
+}
+
+// Checkout reserves stock for the products the buyer is about to order
+// @Summary Start Checkout
+// @Description Reserve stock for the selected products for a limited time so it can't be sold to another buyer before the order is placed. Starting a new checkout replaces the previous reservations. The response prices the order as GET /cart/summary does.
+// @Tags User Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param order body []OrderItemRequest true "List of products and quantities"
+// @Success 200 {object} helper.SuccessResponse{data=CheckoutResponse} "Stock reserved successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input"
+// @Failure 409 {object} helper.ErrorResponse "Not enough stock available"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /checkout [post]
+func Checkout(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract buyer ID
+
+	var items []OrderItemRequest
+	if err := c.ShouldBindJSON(&items); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input: " + err.Error()})
+		return
+	}
+	if len(items) == 0 {
+		helper.SendError(c, http.StatusBadRequest, []string{"No products selected for checkout"})
+		return
+	}
+
+	// The same product listed twice is reserved once for the total quantity
+	var checkoutItems []OrderItemRequest
+	positions := make(map[uint64]int)
+	for _, item := range items {
+		if i, ok := positions[item.ProductID]; ok {
+			checkoutItems[i].Quantity += item.Quantity
+			continue
+		}
+		positions[item.ProductID] = len(checkoutItems)
+		checkoutItems = append(checkoutItems, item)
+	}
+
+	expiresAt := time.Now().Add(config.ReservationTTL())
+	response := CheckoutResponse{
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+		Items:     []ReservationResponse{},
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := _releaseReservations(tx, buyerID); err != nil {
+			return err
+		}
+
+		// Checkout prices and checks stock the way the cart summary does
+		lines, err := _requestCartLines(tx, buyerID, checkoutItems)
+		if err != nil {
+			return err
+		}
+		priced, err := _priceCart(tx, lines, buyerID, true)
+		if err != nil {
+			return err
+		}
+		if unavailable := priced.blockers(); len(unavailable) > 0 {
+			return &errStockUnavailable{messages: unavailable}
+		}
+
+		for _, line := range priced.lines {
+			response.Items = append(response.Items, ReservationResponse{
+				ProductID:   line.product.ID,
+				ProductName: line.product.Name,
+				Quantity:    line.quantity,
+				Price:       line.price,
+			})
+		}
+		response.Summary = priced.summary
+
+		// Reservations hold the stock itself, the components' for a bundle
+		for _, productID := range _demandProductIDs(priced.demand) {
+			if err := tx.Create(&models.StockReservation{
+				ProductID: productID,
+				BuyerID:   buyerID,
+				Quantity:  priced.demand[productID],
+				Status:    models.ReservationActive,
+				ExpiresAt: expiresAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var unavailableErr *errStockUnavailable
+	if errors.As(err, &unavailableErr) {
+		helper.SendError(c, http.StatusConflict, unavailableErr.messages)
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to reserve stock"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Stock reserved successfully", response)
+}
+
+// CancelCheckout releases the stock reserved by the buyer's checkout
+// @Summary Cancel Checkout
+// @Description Release the stock reserved by the buyer's current checkout
+// @Tags User Orders
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} helper.SuccessResponse "Checkout cancelled successfully"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /checkout [delete]
+func CancelCheckout(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract buyer ID
+
+	if err := _releaseReservations(config.DB, buyerID); err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to cancel checkout"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Checkout cancelled successfully", nil)
+}
+
+// _releaseReservations releases every active reservation of the buyer
+func _releaseReservations(tx *gorm.DB, buyerID uint64) error {
+	return tx.Model(&models.StockReservation{}).
+		Where("buyer_id = ? AND status = ?", buyerID, models.ReservationActive).
+		Update("status", models.ReservationReleased).Error
+}
@@ -0,0 +1,303 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cartTokenHeader carries the opaque token that identifies a guest cart
+const cartTokenHeader = "X-Cart-Token"
+
+// GuestAddToCart adds goods to a guest cart
+// @Summary Add Product to Guest Cart
+// @Description Add a product to the cart of a guest. Without a known cart token a new guest cart is created, the returned cart_token identifies it in later requests and is merged into the buyer's cart on sign in or registration.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "Guest cart token"
+// @Param payload body AddToCartRequest true "Product ID and Quantity"
+// @Success 200 {object} helper.SuccessResponse "Product added to cart successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /guest/cart [post]
+func GuestAddToCart(c *gin.Context) {
+	var req AddToCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	var product models.Product
+	if err := config.DB.Scopes(models.PublicProducts).First(&product, req.ProductID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	token := c.GetHeader(cartTokenHeader)
+	var cartItem *models.CartItem
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		cart, err := _guestCart(tx, token)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Missing, stale and swept tokens all start a new cart
+			cart, token, err = _createGuestCart(tx)
+		}
+		if err != nil {
+			return err
+		}
+
+		cartItem, err = _addCartItem(tx, cart.ID, product, req.Quantity)
+		return err
+	})
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to add product to cart"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product added to cart successfully", gin.H{
+		"cart_token":   token,
+		"cart_item_id": cartItem.ID,
+		"product":      product.Name,
+		"quantity":     req.Quantity,
+	})
+}
+
+// GetGuestCart retrieves the items of a guest cart
+// @Summary Get Guest Cart Items
+// @Description Retrieve all items of a guest cart, priced at the price in effect now
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string true "Guest cart token"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]CartItemResponse} "List of cart items"
+// @Failure 400 {object} helper.ErrorResponse "Cart token missing"
+// @Failure 404 {object} helper.ErrorResponse "Cart not found"
+// @Failure 500 {object} helper.ErrorResponse "Failed to retrieve cart items"
+// @Router /guest/cart [get]
+func GetGuestCart(c *gin.Context) {
+	cart, ok := _requireGuestCart(c)
+	if !ok {
+		return
+	}
+
+	_sendCartItems(c, cart.ID)
+}
+
+// GetGuestCartSummary prices a guest cart
+// @Summary Get Guest Cart Summary
+// @Description Price a guest cart: subtotal, sale discounts, shipping estimate, tax and grand total, with warnings for lines short of stock, with a changed price or with a product no longer sold
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string true "Guest cart token"
+// @Success 200 {object} helper.SuccessResponse{data=CartSummaryResponse} "Cart summary"
+// @Failure 400 {object} helper.ErrorResponse "Cart token missing"
+// @Failure 404 {object} helper.ErrorResponse "Cart not found"
+// @Failure 500 {object} helper.ErrorResponse "Failed to price cart"
+// @Router /guest/cart/summary [get]
+func GetGuestCartSummary(c *gin.Context) {
+	cart, ok := _requireGuestCart(c)
+	if !ok {
+		return
+	}
+
+	// Guests hold no reservations, every reservation is off limits
+	_sendCartSummary(c, cart.ID, 0)
+}
+
+// DeleteGuestCart deletes one or more items from a guest cart
+// @Summary Delete Guest Cart Items
+// @Description Delete one or more items from a guest cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string true "Guest cart token"
+// @Param payload body DeleteCartRequest true "Cart Item IDs to delete"
+// @Success 200 {object} helper.SuccessResponse "Cart items deleted successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 404 {object} helper.ErrorResponse "Cart not found"
+// @Failure 500 {object} helper.ErrorResponse "Failed to delete cart items"
+// @Router /guest/cart [delete]
+func DeleteGuestCart(c *gin.Context) {
+	cart, ok := _requireGuestCart(c)
+	if !ok {
+		return
+	}
+
+	_deleteCartItems(c, cart.ID)
+}
+
+// UpdateGuestCart updates the quantity of a guest cart item
+// @Summary Update Guest Cart Item
+// @Description Update the quantity of a specific item of a guest cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string true "Guest cart token"
+// @Param payload body UpdateCartRequest true "Cart item details"
+// @Success 200 {object} helper.SuccessResponse "Cart item updated successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 404 {object} helper.ErrorResponse "Cart not found"
+// @Failure 500 {object} helper.ErrorResponse "Failed to update cart item"
+// @Router /guest/cart [put]
+func UpdateGuestCart(c *gin.Context) {
+	cart, ok := _requireGuestCart(c)
+	if !ok {
+		return
+	}
+
+	_updateCartItem(c, cart.ID)
+}
+
+// _requireGuestCart looks up the guest cart of the request, responding with
+// an error when there is none
+func _requireGuestCart(c *gin.Context) (*models.Cart, bool) {
+	token := c.GetHeader(cartTokenHeader)
+	if token == "" {
+		helper.SendError(c, http.StatusBadRequest, []string{"Cart token missing"})
+		return nil, false
+	}
+
+	cart, err := _guestCart(config.DB, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Cart not found"})
+		return nil, false
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve cart"})
+		return nil, false
+	}
+	return cart, true
+}
+
+// _guestCart returns the guest cart identified by token
+func _guestCart(tx *gorm.DB, token string) (*models.Cart, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var cart models.Cart
+	err := tx.Where("token_hash = ? AND buyer_id IS NULL", _hashCartToken(token)).First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// _createGuestCart creates a guest cart along with the token that identifies it.
+// Only a hash of the token is stored.
+func _createGuestCart(tx *gorm.DB) (*models.Cart, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(secret)
+
+	tokenHash := _hashCartToken(token)
+	cart := models.Cart{TokenHash: &tokenHash}
+	if err := tx.Create(&cart).Error; err != nil {
+		return nil, "", err
+	}
+	return &cart, token, nil
+}
+
+// _hashCartToken is how a guest cart token is stored and looked up
+func _hashCartToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// _claimGuestCart merges the guest cart sent along with a sign in or a
+// registration into the buyer's cart. A failed merge doesn't fail the sign
+// in, the guest cart is left as it was.
+func _claimGuestCart(c *gin.Context, buyerID uint64) {
+	token := c.GetHeader(cartTokenHeader)
+	if token == "" {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return _mergeGuestCart(tx, token, buyerID)
+	})
+	if err != nil {
+		log.Printf("failed to merge guest cart into the cart of buyer %d: %v", buyerID, err)
+	}
+}
+
+// _mergeGuestCart moves the items of a guest cart into the buyer's cart and
+// deletes the guest cart. Products in both carts get the quantity picked by
+// the configured merge rule.
+func _mergeGuestCart(tx *gorm.DB, token string, buyerID uint64) error {
+	// The lock makes concurrent sign ins with the same token merge only once
+	var guestCart models.Cart
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND buyer_id IS NULL", _hashCartToken(token)).
+		First(&guestCart).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // Stale tokens have nothing to merge
+	}
+	if err != nil {
+		return err
+	}
+
+	cart, err := _getOrCreateCart(tx, buyerID)
+	if err != nil {
+		return err
+	}
+
+	var guestItems []models.CartItem
+	if err := tx.Where("cart_id = ?", guestCart.ID).Find(&guestItems).Error; err != nil {
+		return err
+	}
+
+	rule := config.CartMergeRule()
+	for _, guestItem := range guestItems {
+		var cartItem models.CartItem
+		err := tx.Where("cart_id = ? AND product_id = ?", cart.ID, guestItem.ProductID).First(&cartItem).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Model(&guestItem).Update("cart_id", cart.ID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		quantity := _mergedQuantity(rule, cartItem.Quantity, guestItem.Quantity)
+		if err := tx.Model(&cartItem).Update("quantity", quantity).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&guestItem).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Delete(&guestCart).Error
+}
+
+// _mergedQuantity applies a merge rule to a product in both carts
+func _mergedQuantity(rule string, buyerQuantity, guestQuantity int) int {
+	switch rule {
+	case config.CartMergeMax:
+		return max(buyerQuantity, guestQuantity)
+	case config.CartMergeGuest:
+		return guestQuantity
+	case config.CartMergeBuyer:
+		return buyerQuantity
+	default:
+		return buyerQuantity + guestQuantity
+	}
+}
@@ -10,6 +10,9 @@ type OrderItemResponse struct {
 	ProductName string  `json:"product_name"`
 	Quantity    int     `json:"quantity"`
 	Price       float64 `json:"price"`
+
+	ID           uint64  `json:"id"`
+	BundleItemID *uint64 `json:"bundle_item_id,omitempty"` // Set on the components of a bundle, which are free
 }
 
 type OrderResponse struct {
@@ -24,6 +27,10 @@ type OrderDetailResponse struct {
 	OrderID     uint64  `json:"order_id"`
 	BuyerName   string  `json:"buyer_name"`
 	TotalAmount float64 `json:"total_amount"`
+	Subtotal    float64 `json:"subtotal"`
+	Discount    float64 `json:"discount"`
+	Shipping    float64 `json:"shipping"`
+	Tax         float64 `json:"tax"`
 	Status      string  `json:"status"`
 	CreatedAt   string  `json:"created_at"`
 	UpdatedAt   string  `json:"updated_at"`
@@ -35,6 +42,9 @@ type OrderItemDetailResponse struct {
 	Price       float64 `json:"price"`
 	TotalPrice  float64 `json:"total_price"`
 	ImageURL    string  `json:"image_url"`
+
+	ID           uint64  `json:"id"`
+	BundleItemID *uint64 `json:"bundle_item_id,omitempty"` // Set on the components of a bundle, which are free
 }
 
 type OrderDetailWithItemsResponse struct {
@@ -4,6 +4,7 @@ import (
 	"deketna/config"
 	"deketna/helper"
 	"deketna/models"
+	"deketna/notification"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,9 +17,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// orderLine is an item of a new order with the stock it takes
+type orderLine struct {
+	item       models.OrderItem
+	isBundle   bool
+	components []models.BundleComponent
+}
+
 // PlaceOrder creates a new order for selected products
 // @Summary Place Order
-// @Description Create a new order with selected products, validate stock, deduct quantities
+// @Description Create a new order with selected products, validate stock, deduct quantities. Bundles deduct the stock of their components, which are recorded as items of the bundle. Stock reserved by the buyer's checkout is used, stock reserved by other buyers is not available. The total is the grand total of GET /cart/summary, shipping and tax included, and the order keeps its subtotal, discount, shipping and tax.
 // @Tags User Orders
 // @Accept json
 // @Produce json
@@ -46,43 +54,45 @@ func PlaceOrder(c *gin.Context) {
 	}
 
 	// Step 3: Begin Database Transaction
+	var lowStock []models.LowStockProduct
 	err := config.DB.Transaction(func(tx *gorm.DB) error {
-		var totalAmount float64
-		var validOrderItems []models.OrderItem
-
-		var insufficientStock []string
-
-		for _, item := range orderItems {
-			var product models.Product
-			if err := tx.Set("gorm:query_option", "FOR UPDATE").
-				First(&product, item.ProductID).Error; err != nil {
-				insufficientStock = append(insufficientStock, fmt.Sprintf("product not found: %d", item.ProductID))
-				continue
-			}
-
-			// Check stock availability
-			if product.Stock < item.Quantity {
-				insufficientStock = append(insufficientStock, fmt.Sprintf("insufficient stock for product: %s", product.Name))
-				continue
-			}
-
-			totalAmount += float64(item.Quantity) * product.Price
-			validOrderItems = append(validOrderItems, models.OrderItem{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     product.Price,
-			})
+		// Price and check stock the way the cart summary and checkout do
+		lines, err := _requestCartLines(tx, buyerID, orderItems)
+		if err != nil {
+			return fmt.Errorf("failed to look up cart: %v", err)
+		}
+		priced, err := _priceCart(tx, lines, buyerID, true)
+		if err != nil {
+			return fmt.Errorf("failed to price order: %v", err)
 		}
 
 		// If there are any stock errors, return them
-		if len(insufficientStock) > 0 {
-			return errors.New(strings.Join(insufficientStock, "; "))
+		if unavailable := priced.blockers(); len(unavailable) > 0 {
+			return errors.New(strings.Join(unavailable, "; "))
 		}
 
+		var validOrderItems []orderLine
+		for _, line := range priced.lines {
+			validOrderItems = append(validOrderItems, orderLine{
+				item: models.OrderItem{
+					ProductID: line.product.ID,
+					Quantity:  line.quantity,
+					Price:     line.price,
+				},
+				isBundle:   line.product.Type == models.ProductTypeBundle,
+				components: line.components,
+			})
+		}
+		demand := priced.demand
+
 		// Create Order
 		order := models.Order{
 			BuyerID:     buyerID,
-			TotalAmount: totalAmount,
+			TotalAmount: priced.summary.GrandTotal,
+			Subtotal:    priced.summary.Subtotal,
+			Discount:    priced.summary.Discount,
+			Shipping:    priced.summary.Shipping,
+			Tax:         priced.summary.Tax,
 			Status:      "pending",
 		}
 		if err := tx.Create(&order).Error; err != nil {
@@ -90,17 +100,42 @@ func PlaceOrder(c *gin.Context) {
 		}
 
 		// Create Order Items and Deduct Stock
-		for _, item := range validOrderItems {
+		for _, line := range validOrderItems {
+			item := line.item
 			item.OrderID = order.ID
 			if err := tx.Create(&item).Error; err != nil {
 				return fmt.Errorf("failed to create order item: %v", err)
 			}
 
-			// Deduct stock
-			if err := tx.Model(&models.Product{}).
-				Where("id = ?", item.ProductID).
-				UpdateColumn("stock", gorm.Expr("stock - ?", item.Quantity)).Error; err != nil {
-				return fmt.Errorf("failed to deduct stock for product ID: %d", item.ProductID)
+			// A bundle's stock is taken from its components, each recorded as an item of the bundle
+			stockItems := []models.OrderItem{item}
+			if line.isBundle {
+				stockItems = nil
+				for _, component := range line.components {
+					componentItem := models.OrderItem{
+						OrderID:      order.ID,
+						ProductID:    component.ComponentID,
+						Quantity:     component.Quantity * item.Quantity,
+						BundleItemID: &item.ID,
+					}
+					if err := tx.Create(&componentItem).Error; err != nil {
+						return fmt.Errorf("failed to create order item: %v", err)
+					}
+					stockItems = append(stockItems, componentItem)
+				}
+			}
+
+			// Deduct stock through the inventory ledger
+			for _, stockItem := range stockItems {
+				if err := models.ApplyStockMovement(tx, &models.StockMovement{
+					ProductID:   stockItem.ProductID,
+					Delta:       -stockItem.Quantity,
+					Reason:      models.StockReasonSale,
+					ReferenceID: fmt.Sprintf("order:%d", order.ID),
+					ActorID:     &buyerID,
+				}); err != nil {
+					return fmt.Errorf("failed to deduct stock for product ID: %d", stockItem.ProductID)
+				}
 			}
 		}
 
@@ -108,8 +143,21 @@ func PlaceOrder(c *gin.Context) {
 		for _, item := range orderItems {
 			productIDs = append(productIDs, item.ProductID)
 		}
+		stockedIDs := _demandProductIDs(demand)
 
-		if err := tx.Where("product_id IN ?", productIDs).
+		// The buyer's checkout reservations for these products are now fulfilled
+		if err := tx.Model(&models.StockReservation{}).
+			Where("buyer_id = ? AND product_id IN ? AND status = ?", buyerID, stockedIDs, models.ReservationActive).
+			Updates(map[string]interface{}{"status": models.ReservationConverted, "order_id": order.ID}).Error; err != nil {
+			return fmt.Errorf("failed to convert stock reservations: %v", err)
+		}
+
+		// Sales that take stock down to the reorder threshold alert the admins
+		if lowStock, err = models.ClaimLowStockAlerts(tx, stockedIDs); err != nil {
+			return fmt.Errorf("failed to check stock levels: %v", err)
+		}
+
+		if err := tx.Where("product_id IN ? AND cart_id IN (SELECT id FROM carts WHERE buyer_id = ?)", productIDs, buyerID).
 			Delete(&models.CartItem{}).Error; err != nil {
 			return fmt.Errorf("failed to remove items from cart: %v", err)
 		}
@@ -121,6 +169,10 @@ func PlaceOrder(c *gin.Context) {
 		return
 	}
 
+	for _, product := range lowStock {
+		_notifyLowStock(product)
+	}
+
 	// Success Response
 	helper.SendSuccess(c, http.StatusOK, "Order placed successfully", gin.H{
 		"message": "Order  placed successfully",
@@ -198,12 +250,15 @@ func ViewOrders(c *gin.Context) {
 	if len(orderIDs) > 0 {
 		err = config.DB.Table("order_items").
 			Select(`
+				order_items.id,
+				order_items.bundle_item_id,
 				order_items.order_id,
 				products.name AS product_name,
 				order_items.quantity,
 				order_items.price`).
 			Joins("JOIN products ON products.id = order_items.product_id").
 			Where("order_items.order_id IN ?", orderIDs).
+			Order("order_items.id ASC").
 			Scan(&items).Error
 
 		if err != nil {
@@ -289,6 +344,10 @@ func GetOrderItemsDetail(c *gin.Context) {
 			orders.id AS order_id,
 			COALESCE(profiles.name, '') AS buyer_name,
 			orders.total_amount,
+			orders.subtotal,
+			orders.discount,
+			orders.shipping,
+			orders.tax,
 			orders.status,
 			orders.created_at,
 			orders.updated_at`).
@@ -305,6 +364,8 @@ func GetOrderItemsDetail(c *gin.Context) {
 	var orderItems []OrderItemDetailResponse
 	err = config.DB.Table("order_items").
 		Select(`
+			order_items.id,
+			order_items.bundle_item_id,
 			products.name AS product_name,
 			order_items.quantity,
 			order_items.price,
@@ -312,6 +373,7 @@ func GetOrderItemsDetail(c *gin.Context) {
 			products.image_url`).
 		Joins("JOIN products ON products.id = order_items.product_id").
 		Where("order_items.order_id = ?", orderID).
+		Order("order_items.id ASC").
 		Scan(&orderItems).Error
 
 	if err != nil {
@@ -331,3 +393,13 @@ func GetOrderItemsDetail(c *gin.Context) {
 	// Step 7: Send Response
 	helper.SendSuccess(c, http.StatusOK, "Order details fetched successfully", finalResponse)
 }
+
+func _notifyLowStock(product models.LowStockProduct) {
+	notification.Notify(notification.Message{
+		Subject: fmt.Sprintf("Low stock: %s", product.Name),
+		Body: fmt.Sprintf(
+			"%s (SKU %s, ID %d) is down to %d in stock, at or below its reorder threshold of %d.\nYou won't be alerted again until it is restocked.",
+			product.Name, product.SKU, product.ID, product.Stock, product.ReorderThreshold,
+		),
+	})
+}
@@ -0,0 +1,256 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/models"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// Warning codes of cart lines
+const (
+	cartWarningInsufficientStock  = "insufficient_stock"
+	cartWarningPriceChanged       = "price_changed"
+	cartWarningProductArchived    = "product_archived"
+	cartWarningProductUnavailable = "product_unavailable"
+)
+
+// cartLine is a product and quantity to price. addedPrice is the price per
+// unit when the product was put in the cart, 0 when unknown.
+type cartLine struct {
+	cartItemID uint64
+	productID  uint64
+	quantity   int
+	addedPrice float64
+}
+
+// pricedLine is a cart line that can be ordered, with the stock it takes
+type pricedLine struct {
+	index      int // Of the line in the summary items
+	product    models.Product
+	quantity   int
+	price      float64
+	components []models.BundleComponent
+}
+
+// pricedCart is a cart as priced by _priceCart
+type pricedCart struct {
+	summary CartSummaryResponse
+	lines   []pricedLine   // The lines that can be ordered
+	demand  map[uint64]int // Stock needed per product by those lines
+}
+
+// blockers lists the warnings that keep the cart from being ordered. A price
+// change doesn't, orders are charged the price in effect now.
+func (p *pricedCart) blockers() []string {
+	var messages []string
+	for _, item := range p.summary.Items {
+		for _, warning := range item.Warnings {
+			if warning.Code != cartWarningPriceChanged {
+				messages = append(messages, warning.Message)
+			}
+		}
+	}
+	return messages
+}
+
+// _priceCart prices cart lines at the prices in effect now, checks them
+// against the stock not reserved by other buyers and adds up the totals the
+// buyer pays. Lines that can't be ordered are warned about and left out of
+// the totals. With lock the products are locked up front, as placing an order
+// needs.
+func _priceCart(tx *gorm.DB, lines []cartLine, buyerID uint64, lock bool) (*pricedCart, error) {
+	priced := &pricedCart{
+		summary: CartSummaryResponse{Items: []CartSummaryItem{}},
+		demand:  make(map[uint64]int),
+	}
+
+	if lock {
+		if err := _lockStockProducts(tx, lines); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, line := range lines {
+		item := CartSummaryItem{
+			CartItemID: line.cartItemID,
+			ProductID:  line.productID,
+			Quantity:   line.quantity,
+			Warnings:   []CartWarning{},
+		}
+
+		// Archived products are soft deleted, they are looked up to tell the buyer
+		var product models.Product
+		err := tx.Unscoped().First(&product, line.productID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			item.Warnings = append(item.Warnings, CartWarning{
+				Code:    cartWarningProductUnavailable,
+				Message: fmt.Sprintf("product not found: %d", line.productID),
+			})
+			priced.summary.Items = append(priced.summary.Items, item)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		item.ProductName = product.Name
+
+		var components []models.BundleComponent
+		switch {
+		case product.DeletedAt.Valid || product.Status == models.ProductStatusArchived:
+			item.Warnings = append(item.Warnings, CartWarning{
+				Code:    cartWarningProductArchived,
+				Message: fmt.Sprintf("product is no longer sold: %s", product.Name),
+			})
+		case !product.IsPublic():
+			item.Warnings = append(item.Warnings, CartWarning{
+				Code:    cartWarningProductUnavailable,
+				Message: fmt.Sprintf("product is not available: %s", product.Name),
+			})
+		default:
+			components, err = _addStockDemand(tx, priced.demand, product, line.quantity)
+			if errors.Is(err, models.ErrEmptyBundle) {
+				item.Warnings = append(item.Warnings, CartWarning{
+					Code:    cartWarningProductUnavailable,
+					Message: fmt.Sprintf("bundle has no components: %s", product.Name),
+				})
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if len(item.Warnings) > 0 {
+			priced.summary.Items = append(priced.summary.Items, item)
+			continue
+		}
+
+		price, err := models.EffectivePrice(tx, product)
+		if err != nil {
+			return nil, err
+		}
+		regularPrice := product.Price
+
+		item.Price = price
+		item.Subtotal = _roundAmount(regularPrice * float64(line.quantity))
+		item.Total = _roundAmount(price * float64(line.quantity))
+		item.Discount = _roundAmount(item.Subtotal - item.Total)
+		if price < regularPrice {
+			item.CompareAtPrice = &regularPrice
+		}
+
+		if line.addedPrice > 0 && _roundAmount(line.addedPrice) != _roundAmount(price) {
+			item.Warnings = append(item.Warnings, CartWarning{
+				Code:    cartWarningPriceChanged,
+				Message: fmt.Sprintf("price of %s changed from %.2f to %.2f", product.Name, line.addedPrice, price),
+			})
+		}
+
+		priced.lines = append(priced.lines, pricedLine{
+			index:      len(priced.summary.Items),
+			product:    product,
+			quantity:   line.quantity,
+			price:      price,
+			components: components,
+		})
+		priced.summary.Items = append(priced.summary.Items, item)
+	}
+
+	// Stock is checked over all lines at once, bundles share their components
+	shortages, err := _stockShortages(tx, priced.demand, buyerID, lock)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range priced.lines {
+		for _, component := range line.components {
+			if message, short := shortages[component.ComponentID]; short {
+				item := &priced.summary.Items[line.index]
+				item.Warnings = append(item.Warnings, CartWarning{Code: cartWarningInsufficientStock, Message: message})
+				break
+			}
+		}
+	}
+
+	_addCartTotals(&priced.summary, priced.lines)
+	return priced, nil
+}
+
+// _addCartTotals adds up the lines that can be ordered and estimates
+// shipping and tax on top
+func _addCartTotals(summary *CartSummaryResponse, lines []pricedLine) {
+	pricing := config.CartPricing()
+
+	var weightGrams float64
+	for _, line := range lines {
+		item := summary.Items[line.index]
+		summary.Subtotal += item.Subtotal
+		summary.Discount += item.Discount
+		weightGrams += line.product.WeightGrams * float64(line.quantity)
+	}
+	summary.Subtotal = _roundAmount(summary.Subtotal)
+	summary.Discount = _roundAmount(summary.Discount)
+	discounted := summary.Subtotal - summary.Discount
+
+	freeShipping := pricing.FreeShippingThreshold > 0 && discounted >= pricing.FreeShippingThreshold
+	if len(lines) > 0 && !freeShipping {
+		summary.Shipping = _roundAmount(pricing.ShippingBaseRate + pricing.ShippingRatePerKG*weightGrams/1000)
+	}
+
+	summary.Tax = _roundAmount(discounted * pricing.TaxRate / 100)
+	summary.GrandTotal = _roundAmount(discounted + summary.Shipping + summary.Tax)
+}
+
+// _cartLines lists the items of a cart as cart lines, most recently changed first
+func _cartLines(tx *gorm.DB, cartID uint64) ([]cartLine, error) {
+	var cartItems []models.CartItem
+	if err := tx.Where("cart_id = ?", cartID).Order("updated_at DESC").Find(&cartItems).Error; err != nil {
+		return nil, err
+	}
+
+	lines := make([]cartLine, 0, len(cartItems))
+	for _, cartItem := range cartItems {
+		lines = append(lines, cartLine{
+			cartItemID: cartItem.ID,
+			productID:  cartItem.ProductID,
+			quantity:   cartItem.Quantity,
+			addedPrice: cartItem.UnitPrice,
+		})
+	}
+	return lines, nil
+}
+
+// _requestCartLines turns requested products into cart lines, with the price
+// the buyer's cart remembers for those in it
+func _requestCartLines(tx *gorm.DB, buyerID uint64, items []OrderItemRequest) ([]cartLine, error) {
+	productIDs := make([]uint64, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var cartItems []models.CartItem
+	if err := tx.Where("product_id IN ? AND cart_id IN (SELECT id FROM carts WHERE buyer_id = ?)", productIDs, buyerID).
+		Find(&cartItems).Error; err != nil {
+		return nil, err
+	}
+	inCart := make(map[uint64]models.CartItem, len(cartItems))
+	for _, cartItem := range cartItems {
+		inCart[cartItem.ProductID] = cartItem
+	}
+
+	lines := make([]cartLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, cartLine{
+			cartItemID: inCart[item.ProductID].ID,
+			productID:  item.ProductID,
+			quantity:   item.Quantity,
+			addedPrice: inCart[item.ProductID].UnitPrice,
+		})
+	}
+	return lines, nil
+}
+
+// _roundAmount rounds an amount of money to cents
+func _roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -3,17 +3,58 @@ package user
 type ProductWithSeller struct {
 	ID         uint64  `json:"id"`
 	Name       string  `json:"name"`
-	Price      float64 `json:"price"`
-	Stock      int     `json:"stock"`
+	Slug       string  `json:"slug"`
+	Price      float64 `json:"price"` // Sale price while a sale is running
+	Stock      int     `json:"stock"` // On-hand stock minus active checkout reservations
 	ImageURL   string  `json:"image_url"`
+	Brand      string  `json:"brand"`
 	SellerID   uint64  `json:"seller_id"`
 	SellerName string  `json:"seller_name,omitempty"` // Omitempty for null values
+
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int     `json:"rating_count"`
+
+	CompareAtPrice *float64 `json:"compare_at_price"` // Regular price to strike through during a sale, null otherwise
+
+	Type string `json:"type" example:"simple"` // simple or bundle
+}
+
+type ProductImageResponse struct {
+	ID        uint64 `json:"id"`
+	URL       string `json:"url"` // Large rendition
+	AltText   string `json:"alt_text"`
+	Position  int    `json:"position"`
+	IsPrimary bool   `json:"is_primary"`
+
+	MediumURL    string `json:"medium_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+type ProductSpecification struct {
+	Key   string `json:"key" example:"volume"`
+	Label string `json:"label" example:"Volume"`
+	Value string `json:"value" example:"500"`
+	Unit  string `json:"unit" example:"ml"`
+}
+
+type ProductDetailWithSeller struct {
+	ProductWithSeller
+	Description    string                 `json:"description"` // Sanitized HTML
+	WeightGrams    float64                `json:"weight_grams"`
+	LengthCM       float64                `json:"length_cm"`
+	WidthCM        float64                `json:"width_cm"`
+	HeightCM       float64                `json:"height_cm"`
+	Images         []ProductImageResponse `json:"images" gorm:"-"`
+	Specifications []ProductSpecification `json:"specifications" gorm:"-"`
+	IsWishlisted   *bool                  `json:"is_wishlisted,omitempty" gorm:"-"` // Only set for signed in callers
+
+	BundleItems []BundleItemResponse `json:"bundle_items,omitempty" gorm:"-"` // What a bundle contains
 }
 
-type ProductDetail struct {
-	ID          uint64  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
+type BundleItemResponse struct {
+	ProductID uint64 `json:"product_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ImageURL  string `json:"image_url"`
+	Quantity  int    `json:"quantity"`
 }
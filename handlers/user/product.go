@@ -4,10 +4,15 @@ import (
 	"deketna/config"
 	"deketna/helper"
 	"deketna/models"
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // GetProducts retrieves a paginated list of products with seller details
@@ -19,6 +24,9 @@ import (
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Number of items per page (default: 25)"
 // @Param search_product query string false "Search specific product by keyword (default: "botol")"
+// @Param category_id query int false "Only products of this category"
+// @Param brand query string false "Only products of this brand (case insensitive)"
+// @Param attr[key] query string false "Only products whose specification attribute `key` equals the value, e.g. attr[color]=red"
 // @Success 200 {object} helper.PaginationResponse{data=[]ProductWithSeller} "List of products with seller details"
 // @Failure 400 {object} helper.ErrorResponse "Invalid query parameters"
 // @Router /products [get]
@@ -47,38 +55,64 @@ func GetProducts(c *gin.Context) {
 	var products []ProductWithSeller
 	var totalItems int64
 
-	query := config.DB.Table("products").
+	filter := config.DB.Table("products").Scopes(models.PublicProducts)
+
+	if searchProduct != nil {
+		filter = filter.Where("LOWER(products.name) ILIKE LOWER(?)", "%"+*searchProduct+"%")
+
+	}
+
+	if categoryID, err := strconv.ParseUint(c.Query("category_id"), 10, 64); err == nil {
+		filter = filter.Where("products.category_id = ?", categoryID)
+	}
+
+	if brand := c.Query("brand"); brand != "" {
+		filter = filter.Where("LOWER(products.brand) = LOWER(?)", brand)
+	}
+
+	for key, value := range c.QueryMap("attr") {
+		filter = filter.Where(
+			"EXISTS (SELECT 1 FROM product_attributes WHERE product_attributes.product_id = products.id AND product_attributes.key = ? AND LOWER(product_attributes.value) = LOWER(?))",
+			key, value,
+		)
+	}
+
+	// Get total count for pagination
+	if err := filter.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve products"})
+		return
+	}
+
+	err = filter.Session(&gorm.Session{}).
 		Select(`
 			products.id, 
 			products.name, 
-			products.price, 
-			products.stock, 
+			products.slug, 
+			` + models.EffectivePriceSQL + ` AS price, 
+			` + models.CompareAtPriceSQL + ` AS compare_at_price, 
+			` + models.AvailableStockSQL + ` AS stock, 
 			products.image_url, 
+			products.brand, 
+			products.rating_average, 
+			products.rating_count, 
+			products.type, 
 			users.id AS seller_id, 
 			CASE 
 				WHEN users.id = 1 THEN 'Deketna'
 				ELSE COALESCE(profiles.name, '')
 			END AS seller_name `).
 		Joins("JOIN users ON users.id = products.seller_id").
+		Joins(models.ActiveSaleJoin).
 		Joins("LEFT JOIN profiles ON profiles.user_id = users.id").
 		Limit(limit).
-		Offset(offset)
-
-	if searchProduct != nil {
-		query = query.Where("LOWER(products.name) ILIKE LOWER(?)", "%"+*searchProduct+"%")
-
-	}
-
-	query.Scan(&products)
+		Offset(offset).
+		Scan(&products).Error
 
-	if query.Error != nil {
+	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve products"})
 		return
 	}
 
-	// Get total count for pagination
-	config.DB.Model(&models.Product{}).Count(&totalItems)
-
 	// Build pagination metadata
 	totalPages := (int(totalItems) + limit - 1) / limit
 	pagination := helper.PaginationMetadata{
@@ -101,7 +135,8 @@ func GetProducts(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
-// @Success 200 {object} helper.SuccessResponse{data=ProductWithSeller} "Product details with seller information"
+// @Param Authorization header string false "Optional bearer token, adds is_wishlisted to the response and records the view for buyers"
+// @Success 200 {object} helper.SuccessResponse{data=ProductDetailWithSeller} "Product details with seller information and gallery"
 // @Failure 400 {object} helper.ErrorResponse "Invalid Product ID"
 // @Failure 404 {object} helper.ErrorResponse "Product not found"
 // @Router /product/{id} [get]
@@ -115,24 +150,87 @@ func GetProductDetail(c *gin.Context) {
 		return
 	}
 
+	_sendProductDetail(c, productID)
+}
+
+// GetProductBySlug retrieves the details of a product by its slug
+// @Summary Get Product Detail by Slug
+// @Description Retrieve the details of a product by its slug. A slug the product had before a rename redirects permanently to the current one.
+// @Tags  Product
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Param Authorization header string false "Optional bearer token, adds is_wishlisted to the response and records the view for buyers"
+// @Success 200 {object} helper.SuccessResponse{data=ProductDetailWithSeller} "Product details with seller information and gallery"
+// @Success 301 "Redirect to the current slug"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /product/by-slug/{slug} [get]
+func GetProductBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	var product models.Product
+	err := config.DB.Scopes(models.PublicProducts).Select("id").Where("slug = ?", slug).First(&product).Error
+	if err == nil {
+		_sendProductDetail(c, product.ID)
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve product"})
+		return
+	}
+
+	// Links shared before a rename still lead to the product
+	var current string
+	if err := config.DB.Table("product_slug_redirects").
+		Select("products.slug").
+		Joins("JOIN products ON products.id = product_slug_redirects.product_id").
+		Where("product_slug_redirects.slug = ?", slug).
+		Scopes(models.PublicProducts).
+		Scan(&current).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve product"})
+		return
+	}
+	if current != "" {
+		c.Redirect(http.StatusMovedPermanently, "/product/by-slug/"+url.PathEscape(current))
+		return
+	}
+
+	helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+}
+
+// _sendProductDetail responds with the full details of a public product
+func _sendProductDetail(c *gin.Context, productID uint64) {
 	// Fetch product with seller details using LEFT JOIN
-	var product ProductWithSeller
+	var product ProductDetailWithSeller
 
-	err = config.DB.Table("products").
+	err := config.DB.Table("products").
 		Select(`
 			products.id, 
 			products.name, 
-			products.price, 
-			products.stock, 
+			products.slug, 
+			`+models.EffectivePriceSQL+` AS price, 
+			`+models.CompareAtPriceSQL+` AS compare_at_price, 
+			`+models.AvailableStockSQL+` AS stock, 
 			products.image_url, 
+			products.brand, 
+			products.rating_average, 
+			products.rating_count, 
+			products.type, 
+			products.description_html AS description, 
+			products.weight_grams, 
+			products.length_cm, 
+			products.width_cm, 
+			products.height_cm, 
 			users.id AS seller_id, 
 			CASE 
 				WHEN users.id = 1 THEN 'Deketna'
 				ELSE COALESCE(profiles.name, '')
 			END AS seller_name `).
 		Joins("JOIN users ON users.id = products.seller_id").
+		Joins(models.ActiveSaleJoin).
 		Joins("LEFT JOIN profiles ON profiles.user_id = users.id").
 		Where("products.id = ?", productID).
+		Scopes(models.PublicProducts).
 		Scan(&product).Error
 
 	if err != nil {
@@ -147,6 +245,79 @@ func GetProductDetail(c *gin.Context) {
 		return
 	}
 
+	// Fetch the full gallery in display order
+	var images []ProductImageResponse
+	err = config.DB.Model(&models.ProductImage{}).
+		Select("id, url, medium_url, thumbnail_url, alt_text, position, is_primary").
+		Where("product_id = ?", productID).
+		Order("position ASC, id ASC").
+		Scan(&images).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve product images"})
+		return
+	}
+
+	// Fetch specification attributes, labelled by the category schema
+	var specifications []ProductSpecification
+	err = config.DB.Table("product_attributes").
+		Select(`
+			product_attributes.key,
+			product_attributes.value,
+			COALESCE(category_attributes.label, product_attributes.key) AS label,
+			COALESCE(category_attributes.unit, '') AS unit`).
+		Joins("JOIN products ON products.id = product_attributes.product_id").
+		Joins("LEFT JOIN category_attributes ON category_attributes.category_id = products.category_id AND category_attributes.key = product_attributes.key").
+		Where("product_attributes.product_id = ?", productID).
+		Order("category_attributes.position ASC, product_attributes.key ASC").
+		Scan(&specifications).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve product specifications"})
+		return
+	}
+
+	product.Images = images
+	product.Specifications = specifications
+
+	if product.Type == models.ProductTypeBundle {
+		err = config.DB.Table("bundle_components").
+			Select(`
+				products.id AS product_id,
+				products.name,
+				products.slug,
+				products.image_url,
+				bundle_components.quantity`).
+			Joins("JOIN products ON products.id = bundle_components.component_id").
+			Where("bundle_components.bundle_id = ?", productID).
+			Order("products.name ASC").
+			Scan(&product.BundleItems).Error
+
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve bundle items"})
+			return
+		}
+	}
+
+	// Signed in callers also learn whether the product is on their wishlist,
+	// and buyers get the view added to their recently viewed history
+	if value, ok := c.Get("claims"); ok {
+		claims := value.(jwt.MapClaims)
+		userID := uint64(claims["userid"].(float64))
+		isWishlisted, err := _isWishlisted(config.DB, userID, productID)
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve wishlist state"})
+			return
+		}
+		product.IsWishlisted = &isWishlisted
+
+		if claims["role"] == "buyer" {
+			if err := _recordProductView(config.DB, userID, productID); err != nil {
+				log.Printf("failed to record view of product %d: %v", productID, err)
+			}
+		}
+	}
+
 	// Send success response
 	helper.SendSuccess(c, http.StatusOK, "Product details retrieved successfully", product)
 }
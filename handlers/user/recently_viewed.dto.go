@@ -0,0 +1,11 @@
+package user
+
+type RecentlyViewedResponse struct {
+	ID             uint64   `json:"id"`
+	Name           string   `json:"name"`
+	Price          float64  `json:"price"`
+	CompareAtPrice *float64 `json:"compare_at_price"`
+	Stock          int      `json:"stock"`
+	ImageURL       string   `json:"image_url"`
+	ViewedAt       string   `json:"viewed_at"`
+}
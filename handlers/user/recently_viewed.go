@@ -0,0 +1,110 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Only the latest views are kept per user
+const maxRecentlyViewed = 50
+
+// GetRecentlyViewed lists the products the buyer viewed last
+// @Summary Get Recently Viewed Products
+// @Description Retrieve the products the buyer viewed most recently. Products that were archived or are out of stock are left out.
+// @Tags Product
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of products (default: 20, max: 50)"
+// @Success 200 {object} helper.SuccessResponse{data=[]RecentlyViewedResponse} "Recently viewed products"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /products/recently-viewed [get]
+func GetRecentlyViewed(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > maxRecentlyViewed {
+		limit = maxRecentlyViewed
+	}
+
+	products := []RecentlyViewedResponse{}
+	err = config.DB.Table("recently_viewed_products").
+		Select(`
+			products.id,
+			products.name,
+			`+models.EffectivePriceSQL+` AS price,
+			`+models.CompareAtPriceSQL+` AS compare_at_price,
+			`+models.AvailableStockSQL+` AS stock,
+			products.image_url,
+			recently_viewed_products.viewed_at`).
+		Joins("JOIN products ON products.id = recently_viewed_products.product_id").
+		Joins(models.ActiveSaleJoin).
+		Where("recently_viewed_products.user_id = ?", buyerID).
+		Where(models.AvailableStockSQL + " > 0").
+		Scopes(models.PublicProducts).
+		Order("recently_viewed_products.viewed_at DESC").
+		Limit(limit).
+		Scan(&products).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve recently viewed products"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Recently viewed products retrieved successfully", products)
+}
+
+// ClearRecentlyViewed clears the buyer's viewing history
+// @Summary Clear Recently Viewed Products
+// @Description Delete the buyer's recently viewed history
+// @Tags Product
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helper.SuccessResponse "History cleared successfully"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /products/recently-viewed [delete]
+func ClearRecentlyViewed(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	if err := config.DB.Where("user_id = ?", buyerID).Delete(&models.RecentlyViewedProduct{}).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to clear history"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "History cleared successfully", nil)
+}
+
+// _recordProductView moves the product to the top of the user's history and
+// trims the history to its cap
+func _recordProductView(db *gorm.DB, userID, productID uint64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		view := models.RecentlyViewedProduct{UserID: userID, ProductID: productID, ViewedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+		}).Create(&view).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			DELETE FROM recently_viewed_products
+			WHERE user_id = ? AND id NOT IN (
+				SELECT id FROM recently_viewed_products
+				WHERE user_id = ?
+				ORDER BY viewed_at DESC
+				LIMIT ?)`, userID, userID, maxRecentlyViewed).Error
+	})
+}
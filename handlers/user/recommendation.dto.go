@@ -0,0 +1,11 @@
+package user
+
+type RecommendedProduct struct {
+	ID             uint64   `json:"id"`
+	Name           string   `json:"name"`
+	Price          float64  `json:"price"`
+	CompareAtPrice *float64 `json:"compare_at_price"`
+	Stock          int      `json:"stock"`
+	ImageURL       string   `json:"image_url"`
+	Reason         string   `json:"reason" example:"frequently_bought_together"` // frequently_bought_together or best_seller
+}
@@ -0,0 +1,189 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	recommendationReasonAffinity   = "frequently_bought_together"
+	recommendationReasonBestSeller = "best_seller"
+
+	// Pairs bought together fewer times than this are treated as noise
+	minAffinityScore = 2
+
+	defaultRecommendationLimit = 8
+	maxRecommendationLimit     = 24
+)
+
+// GetProductRecommendations lists products frequently bought with a product
+// @Summary Get Product Recommendations
+// @Description Products frequently bought together with this product in finished orders. When there isn't enough order data the list is filled with best-sellers of the same category.
+// @Tags Product
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of recommendations (default: 8, max: 24)"
+// @Success 200 {object} helper.SuccessResponse{data=[]RecommendedProduct} "Recommended products"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /product/{id}/recommendations [get]
+func GetProductRecommendations(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var product models.Product
+	if err := config.DB.Scopes(models.PublicProducts).Select("id", "category_id").First(&product, productID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	var categoryIDs []uint
+	if product.CategoryID != nil {
+		categoryIDs = []uint{*product.CategoryID}
+	}
+
+	recommendations, err := _recommendFor(config.DB, []uint64{productID}, categoryIDs, _recommendationLimit(c))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve recommendations"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Recommendations retrieved successfully", recommendations)
+}
+
+// GetCartRecommendations lists products frequently bought with the cart contents
+// @Summary Get Cart Recommendations
+// @Description Products frequently bought together with the products in the buyer's cart, filled with best-sellers of the same categories when there isn't enough order data
+// @Tags Cart
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of recommendations (default: 8, max: 24)"
+// @Success 200 {object} helper.SuccessResponse{data=[]RecommendedProduct} "Recommended products"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /cart/recommendations [get]
+func GetCartRecommendations(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	var cartProducts []models.Product
+	err := config.DB.Table("products").
+		Select("products.id, products.category_id").
+		Joins("JOIN cart_items ON cart_items.product_id = products.id").
+		Joins("JOIN carts ON carts.id = cart_items.cart_id").
+		Where("carts.buyer_id = ?", buyerID).
+		Scan(&cartProducts).Error
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve cart items"})
+		return
+	}
+
+	var productIDs []uint64
+	var categoryIDs []uint
+	for _, product := range cartProducts {
+		productIDs = append(productIDs, product.ID)
+		if product.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *product.CategoryID)
+		}
+	}
+
+	recommendations, err := _recommendFor(config.DB, productIDs, categoryIDs, _recommendationLimit(c))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve recommendations"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Recommendations retrieved successfully", recommendations)
+}
+
+// _recommendFor ranks products by how often they were bought together with
+// the source products, then tops the list up with best-sellers of the given
+// categories, or of the whole shop when no category is known
+func _recommendFor(db *gorm.DB, sourceIDs []uint64, categoryIDs []uint, limit int) ([]RecommendedProduct, error) {
+	recommendations := []RecommendedProduct{}
+
+	if len(sourceIDs) > 0 {
+		err := _recommendationQuery(db).
+			Joins("JOIN product_affinities ON product_affinities.related_product_id = products.id").
+			Where("product_affinities.product_id IN ? AND products.id NOT IN ?", sourceIDs, sourceIDs).
+			Group("products.id, active_sale.sale_price").
+			Having("SUM(product_affinities.score) >= ?", minAffinityScore).
+			Order("SUM(product_affinities.score) DESC, products.id ASC").
+			Limit(limit).
+			Scan(&recommendations).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range recommendations {
+			recommendations[i].Reason = recommendationReasonAffinity
+		}
+	}
+
+	if len(recommendations) >= limit {
+		return recommendations, nil
+	}
+
+	exclude := append([]uint64{}, sourceIDs...)
+	for _, recommendation := range recommendations {
+		exclude = append(exclude, recommendation.ID)
+	}
+
+	query := _recommendationQuery(db).
+		Joins("LEFT JOIN product_sales_stats ON product_sales_stats.product_id = products.id")
+	if len(categoryIDs) > 0 {
+		query = query.Where("products.category_id IN ?", categoryIDs)
+	}
+	if len(exclude) > 0 {
+		query = query.Where("products.id NOT IN ?", exclude)
+	}
+
+	var bestSellers []RecommendedProduct
+	err := query.
+		Order("COALESCE(product_sales_stats.units_sold, 0) DESC, products.id DESC").
+		Limit(limit - len(recommendations)).
+		Scan(&bestSellers).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, bestSeller := range bestSellers {
+		bestSeller.Reason = recommendationReasonBestSeller
+		recommendations = append(recommendations, bestSeller)
+	}
+
+	return recommendations, nil
+}
+
+// _recommendationQuery selects public products with the price and stock buyers see
+func _recommendationQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("products").
+		Select(`
+			products.id,
+			products.name,
+			` + models.EffectivePriceSQL + ` AS price,
+			` + models.CompareAtPriceSQL + ` AS compare_at_price,
+			` + models.AvailableStockSQL + ` AS stock,
+			products.image_url`).
+		Joins(models.ActiveSaleJoin).
+		Scopes(models.PublicProducts)
+}
+
+func _recommendationLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRecommendationLimit)))
+	if err != nil || limit < 1 {
+		return defaultRecommendationLimit
+	}
+	if limit > maxRecommendationLimit {
+		return maxRecommendationLimit
+	}
+	return limit
+}
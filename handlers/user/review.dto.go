@@ -0,0 +1,25 @@
+package user
+
+type CreateReviewRequest struct {
+	Rating int    `form:"rating" binding:"required,min=1,max=5" example:"5"`
+	Body   string `form:"body" binding:"max=5000" example:"Fresh and well packed"`
+}
+
+type ReviewResponse struct {
+	ID           uint64                `json:"id"`
+	ProductID    uint64                `json:"product_id"`
+	BuyerID      uint64                `json:"buyer_id"`
+	BuyerName    string                `json:"buyer_name"`
+	Rating       int                   `json:"rating"`
+	Body         string                `json:"body"`
+	HelpfulCount int                   `json:"helpful_count"`
+	Photos       []ReviewPhotoResponse `json:"photos" gorm:"-"`
+	CreatedAt    string                `json:"created_at"`
+	UpdatedAt    string                `json:"updated_at"`
+}
+
+type ReviewPhotoResponse struct {
+	URL          string `json:"url"` // Large rendition
+	MediumURL    string `json:"medium_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
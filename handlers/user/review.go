@@ -0,0 +1,395 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"deketna/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxReviewPhotos = 5
+
+var errAlreadyReviewed = errors.New("you have already reviewed this product")
+
+// GetProductReviews retrieves the visible reviews of a product
+// @Summary Get Product Reviews
+// @Description Retrieve a paginated list of visible reviews for a product
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 10)"
+// @Param sort query string false "Sort order: newest, helpful, rating_high or rating_low (default: newest)"
+// @Success 200 {object} helper.PaginationResponse{data=[]ReviewResponse} "List of reviews"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /product/{id}/reviews [get]
+func GetProductReviews(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	order := "product_reviews.created_at DESC"
+	switch c.Query("sort") {
+	case "helpful":
+		order = "product_reviews.helpful_count DESC, product_reviews.created_at DESC"
+	case "rating_high":
+		order = "product_reviews.rating DESC, product_reviews.created_at DESC"
+	case "rating_low":
+		order = "product_reviews.rating ASC, product_reviews.created_at DESC"
+	}
+
+	var totalItems int64
+	if err := config.DB.Model(&models.ProductReview{}).
+		Where("product_id = ? AND is_hidden = ?", productID, false).
+		Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve reviews"})
+		return
+	}
+
+	var reviews []ReviewResponse
+	err = config.DB.Table("product_reviews").
+		Select(`
+			product_reviews.id,
+			product_reviews.product_id,
+			product_reviews.buyer_id,
+			COALESCE(profiles.name, '') AS buyer_name,
+			product_reviews.rating,
+			product_reviews.body,
+			product_reviews.helpful_count,
+			product_reviews.created_at,
+			product_reviews.updated_at`).
+		Joins("LEFT JOIN profiles ON profiles.user_id = product_reviews.buyer_id").
+		Where("product_reviews.product_id = ? AND product_reviews.is_hidden = ?", productID, false).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Scan(&reviews).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve reviews"})
+		return
+	}
+
+	if err := _attachReviewPhotos(config.DB, reviews); err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve review photos"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Reviews retrieved successfully", reviews, pagination)
+}
+
+// CreateReview lets a verified buyer review a product
+// @Summary Review a Product
+// @Description Post a 1-5 rating with optional text and photos. Only buyers with a finished order containing the product can review it, once per product.
+// @Tags Review
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param rating formData integer true "Rating from 1 to 5"
+// @Param body formData string false "Review text"
+// @Param photos formData file false "Review photos (repeat the field, up to 5)"
+// @Success 201 {object} helper.SuccessResponse{data=ReviewResponse} "Review created successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 403 {object} helper.ErrorResponse "Product was not bought by this buyer"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 409 {object} helper.ErrorResponse "Product already reviewed"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /product/{id}/reviews [post]
+func CreateReview(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	var req CreateReviewRequest
+	if err := c.ShouldBind(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	var product models.Product
+	if err := config.DB.Scopes(models.PublicProducts).Select("id").First(&product, productID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	var existing int64
+	config.DB.Model(&models.ProductReview{}).Where("product_id = ? AND buyer_id = ?", productID, buyerID).Count(&existing)
+	if existing > 0 {
+		helper.SendError(c, http.StatusConflict, []string{"You have already reviewed this product"})
+		return
+	}
+
+	// Only a finished order containing the product counts as a verified purchase
+	var orderID uint64
+	err = config.DB.Table("orders").
+		Select("orders.id").
+		Joins("JOIN order_items ON order_items.order_id = orders.id").
+		Where("orders.buyer_id = ? AND orders.status = ? AND order_items.product_id = ?", buyerID, "finish", productID).
+		Order("orders.created_at DESC").
+		Limit(1).
+		Scan(&orderID).Error
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to verify purchase"})
+		return
+	}
+	if orderID == 0 {
+		helper.SendError(c, http.StatusForbidden, []string{"Only buyers who received this product can review it"})
+		return
+	}
+
+	var photos []utils.UploadedImage
+	if form, err := c.MultipartForm(); err == nil {
+		files := form.File["photos"]
+		if len(files) > maxReviewPhotos {
+			helper.SendError(c, http.StatusBadRequest, []string{"A review can have at most 5 photos"})
+			return
+		}
+		for _, file := range files {
+			photo, err := utils.UploadFormImage(file)
+			if errors.Is(err, utils.ErrInvalidImage) {
+				helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+				return
+			}
+			if err != nil {
+				helper.SendError(c, http.StatusInternalServerError, []string{err.Error()})
+				return
+			}
+			photos = append(photos, photo)
+		}
+	}
+
+	review := models.ProductReview{
+		ProductID: productID,
+		BuyerID:   buyerID,
+		OrderID:   orderID,
+		Rating:    req.Rating,
+		Body:      req.Body,
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// A concurrent review by the same buyer can get past the check above,
+		// the unique index settles it
+		result := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyReviewed
+		}
+		for i, photo := range photos {
+			reviewPhoto := models.ProductReviewPhoto{
+				ReviewID:     review.ID,
+				URL:          photo.URL,
+				MediumURL:    photo.MediumURL,
+				ThumbnailURL: photo.ThumbnailURL,
+				Position:     i,
+			}
+			if err := tx.Create(&reviewPhoto).Error; err != nil {
+				return err
+			}
+			if err := models.ReferenceReviewPhotoMedia(tx, reviewPhoto.ID, photo.Keys); err != nil {
+				return err
+			}
+		}
+		return models.RefreshProductRating(tx, productID)
+	})
+	if errors.Is(err, errAlreadyReviewed) {
+		helper.SendError(c, http.StatusConflict, []string{"You have already reviewed this product"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to create review"})
+		return
+	}
+
+	photoResponses := make([]ReviewPhotoResponse, len(photos))
+	for i, photo := range photos {
+		photoResponses[i] = ReviewPhotoResponse{URL: photo.URL, MediumURL: photo.MediumURL, ThumbnailURL: photo.ThumbnailURL}
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Review created successfully", ReviewResponse{
+		ID:        review.ID,
+		ProductID: review.ProductID,
+		BuyerID:   review.BuyerID,
+		Rating:    review.Rating,
+		Body:      review.Body,
+		Photos:    photoResponses,
+		CreatedAt: review.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: review.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// MarkReviewHelpful records a helpful vote on a review
+// @Summary Mark Review Helpful
+// @Description Vote a review as helpful. Voting twice has no effect and buyers can't vote on their own reviews.
+// @Tags Review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param review_id path int true "Review ID"
+// @Success 200 {object} helper.SuccessResponse{data=object{review_id=uint64,helpful_count=int}} "Vote recorded"
+// @Failure 400 {object} helper.ErrorResponse "Invalid review ID"
+// @Failure 404 {object} helper.ErrorResponse "Review not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /review/{review_id}/helpful [post]
+func MarkReviewHelpful(c *gin.Context) {
+	_voteReviewHelpful(c, true)
+}
+
+// UnmarkReviewHelpful removes a helpful vote from a review
+// @Summary Remove Helpful Vote
+// @Description Withdraw a helpful vote from a review
+// @Tags Review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param review_id path int true "Review ID"
+// @Success 200 {object} helper.SuccessResponse{data=object{review_id=uint64,helpful_count=int}} "Vote removed"
+// @Failure 400 {object} helper.ErrorResponse "Invalid review ID"
+// @Failure 404 {object} helper.ErrorResponse "Review not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /review/{review_id}/helpful [delete]
+func UnmarkReviewHelpful(c *gin.Context) {
+	_voteReviewHelpful(c, false)
+}
+
+var errOwnReview = errors.New("you can't vote on your own review")
+
+func _voteReviewHelpful(c *gin.Context, helpful bool) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	userID := uint64(claims["userid"].(float64))
+
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid review ID"})
+		return
+	}
+
+	var review models.ProductReview
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_hidden = ?", reviewID, false).
+			First(&review).Error; err != nil {
+			return err
+		}
+		if review.BuyerID == userID {
+			return errOwnReview
+		}
+
+		var result *gorm.DB
+		if helpful {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.ReviewHelpfulVote{ReviewID: reviewID, UserID: userID})
+		} else {
+			result = tx.Where("review_id = ? AND user_id = ?", reviewID, userID).
+				Delete(&models.ReviewHelpfulVote{})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Only a vote that actually changed moves the counter
+		if result.RowsAffected > 0 {
+			delta := 1
+			if !helpful {
+				delta = -1
+			}
+			review.HelpfulCount += delta
+			return tx.Model(&review).UpdateColumn("helpful_count", gorm.Expr("helpful_count + ?", delta)).Error
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		helper.SendError(c, http.StatusNotFound, []string{"Review not found"})
+		return
+	case errors.Is(err, errOwnReview):
+		helper.SendError(c, http.StatusBadRequest, []string{err.Error()})
+		return
+	case err != nil:
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to record vote"})
+		return
+	}
+
+	message := "Review marked as helpful"
+	if !helpful {
+		message = "Helpful vote removed"
+	}
+	helper.SendSuccess(c, http.StatusOK, message, gin.H{
+		"review_id":     review.ID,
+		"helpful_count": review.HelpfulCount,
+	})
+}
+
+// _attachReviewPhotos loads the photos of the given reviews in one query
+func _attachReviewPhotos(db *gorm.DB, reviews []ReviewResponse) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	reviewIDs := make([]uint64, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+
+	var photos []models.ProductReviewPhoto
+	if err := db.Where("review_id IN ?", reviewIDs).
+		Order("position ASC, id ASC").
+		Find(&photos).Error; err != nil {
+		return err
+	}
+
+	photoMap := make(map[uint64][]ReviewPhotoResponse)
+	for _, photo := range photos {
+		photoMap[photo.ReviewID] = append(photoMap[photo.ReviewID], ReviewPhotoResponse{
+			URL:          photo.URL,
+			MediumURL:    photo.MediumURL,
+			ThumbnailURL: photo.ThumbnailURL,
+		})
+	}
+	for i := range reviews {
+		reviews[i].Photos = photoMap[reviews[i].ID]
+		if reviews[i].Photos == nil {
+			reviews[i].Photos = []ReviewPhotoResponse{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// A sitemap file may list at most 50,000 URLs
+const sitemapMaxURLs = 50000
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+var sitemapFilePattern = regexp.MustCompile(`^(products|categories)-([0-9]+)\.xml$`)
+
+type sitemapURL struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+type sitemapEntry struct {
+	XMLName xml.Name `xml:"sitemap"`
+	Loc     string   `xml:"loc"`
+}
+
+type sitemapRow struct {
+	ID        uint64
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// GetSitemap serves the sitemap of the storefront
+// @Summary Sitemap
+// @Description Sitemap of the active products and the categories. A large catalog gets a sitemap index pointing to the files under /sitemaps instead.
+// @Tags SEO
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /sitemap.xml [get]
+func GetSitemap(c *gin.Context) {
+	var productCount, categoryCount int64
+	if err := config.DB.Model(&models.Product{}).Scopes(models.PublicProducts).Count(&productCount).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to build sitemap"})
+		return
+	}
+	if err := config.DB.Model(&models.Category{}).Count(&categoryCount).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to build sitemap"})
+		return
+	}
+
+	if productCount+categoryCount <= sitemapMaxURLs {
+		siteURL := _siteURL(c)
+		_writeSitemap(c, func(encoder *xml.Encoder) error {
+			if err := _encodeCategoryURLs(encoder, siteURL, 0); err != nil {
+				return err
+			}
+			return _encodeProductURLs(encoder, siteURL, 0)
+		})
+		return
+	}
+
+	// Too many URLs for one file, list the files instead
+	baseURL := _requestBaseURL(c)
+	var entries []sitemapEntry
+	for page := 1; int64(page-1)*sitemapMaxURLs < categoryCount; page++ {
+		entries = append(entries, sitemapEntry{Loc: fmt.Sprintf("%s/sitemaps/categories-%d.xml", baseURL, page)})
+	}
+	for page := 1; int64(page-1)*sitemapMaxURLs < productCount; page++ {
+		entries = append(entries, sitemapEntry{Loc: fmt.Sprintf("%s/sitemaps/products-%d.xml", baseURL, page)})
+	}
+
+	c.Header("Content-Type", "application/xml; charset=utf-8")
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(xml.Header + `<sitemapindex xmlns="` + sitemapNamespace + `">`)
+	encoder := xml.NewEncoder(c.Writer)
+	for _, entry := range entries {
+		encoder.Encode(entry)
+	}
+	encoder.Flush()
+	c.Writer.WriteString("</sitemapindex>\n")
+}
+
+// GetSitemapFile serves one file of a split sitemap
+// @Summary Sitemap File
+// @Description One file of a sitemap split by /sitemap.xml, such as products-2.xml or categories-1.xml
+// @Tags SEO
+// @Produce xml
+// @Param file path string true "File name, e.g. products-1.xml"
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {object} helper.ErrorResponse "Sitemap not found"
+// @Router /sitemaps/{file} [get]
+func GetSitemapFile(c *gin.Context) {
+	match := sitemapFilePattern.FindStringSubmatch(c.Param("file"))
+	if match == nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Sitemap not found"})
+		return
+	}
+	page, err := strconv.Atoi(match[2])
+	if err != nil || page < 1 {
+		helper.SendError(c, http.StatusNotFound, []string{"Sitemap not found"})
+		return
+	}
+
+	offset := (page - 1) * sitemapMaxURLs
+	siteURL := _siteURL(c)
+	_writeSitemap(c, func(encoder *xml.Encoder) error {
+		if match[1] == "categories" {
+			return _encodeCategoryURLs(encoder, siteURL, offset)
+		}
+		return _encodeProductURLs(encoder, siteURL, offset)
+	})
+}
+
+// _writeSitemap streams a urlset, the URLs being encoded by encode
+func _writeSitemap(c *gin.Context, encode func(encoder *xml.Encoder) error) {
+	c.Header("Content-Type", "application/xml; charset=utf-8")
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(xml.Header + `<urlset xmlns="` + sitemapNamespace + `">`)
+
+	encoder := xml.NewEncoder(c.Writer)
+	if err := encode(encoder); err != nil {
+		// The response has started, the truncated document tells crawlers something is wrong
+		c.Error(err)
+		return
+	}
+	encoder.Flush()
+	c.Writer.WriteString("</urlset>\n")
+}
+
+func _encodeProductURLs(encoder *xml.Encoder, siteURL string, offset int) error {
+	query := config.DB.Model(&models.Product{}).
+		Scopes(models.PublicProducts).
+		Select("id, slug, updated_at").
+		Order("id ASC").
+		Offset(offset).
+		Limit(sitemapMaxURLs)
+
+	return _encodeSitemapRows(query, func(row sitemapRow) sitemapURL {
+		return sitemapURL{
+			Loc:     siteURL + "/product/" + url.PathEscape(row.Slug),
+			LastMod: row.UpdatedAt.Format("2006-01-02"),
+		}
+	}, encoder)
+}
+
+func _encodeCategoryURLs(encoder *xml.Encoder, siteURL string, offset int) error {
+	query := config.DB.Model(&models.Category{}).
+		Select("id, updated_at").
+		Order("id ASC").
+		Offset(offset).
+		Limit(sitemapMaxURLs)
+
+	return _encodeSitemapRows(query, func(row sitemapRow) sitemapURL {
+		return sitemapURL{
+			Loc:     fmt.Sprintf("%s/products?category_id=%d", siteURL, row.ID),
+			LastMod: row.UpdatedAt.Format("2006-01-02"),
+		}
+	}, encoder)
+}
+
+// _encodeSitemapRows reads the rows one at a time so large catalogs aren't held in memory
+func _encodeSitemapRows(query *gorm.DB, toURL func(row sitemapRow) sitemapURL, encoder *xml.Encoder) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row sitemapRow
+		if err := config.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := encoder.Encode(toURL(row)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// _siteURL is the storefront address the sitemap links to, read from SITE_URL.
+// Without it the sitemap links to the address the request came in on, as
+// crawlers only accept absolute URLs.
+func _siteURL(c *gin.Context) string {
+	if siteURL := strings.TrimRight(os.Getenv("SITE_URL"), "/"); siteURL != "" {
+		return siteURL
+	}
+	return _requestBaseURL(c)
+}
+
+// _requestBaseURL is the address this API was reached on, where the sitemap files live
+func _requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
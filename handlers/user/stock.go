@@ -0,0 +1,87 @@
+package user
+
+import (
+	"deketna/models"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// _addStockDemand adds the stock quantity units of product take to demand,
+// keyed by the product holding the stock: the components of a bundle, the
+// product itself otherwise. It returns what the stock is taken from.
+func _addStockDemand(tx *gorm.DB, demand map[uint64]int, product models.Product, quantity int) ([]models.BundleComponent, error) {
+	components, err := models.StockComponents(tx, product)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		demand[component.ComponentID] += component.Quantity * quantity
+	}
+	return components, nil
+}
+
+// _lockStockProducts locks the products of lines and the components of the
+// bundles among them in a single pass in id order, the order rows are locked
+// in, so checkouts sharing products in a different order can't deadlock
+func _lockStockProducts(tx *gorm.DB, lines []cartLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	productIDs := make([]uint64, 0, len(lines))
+	for _, line := range lines {
+		productIDs = append(productIDs, line.productID)
+	}
+
+	var locked []uint64
+	return tx.Unscoped().Model(&models.Product{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? OR id IN (SELECT component_id FROM bundle_components WHERE bundle_id IN ?)", productIDs, productIDs).
+		Order("id").
+		Pluck("id", &locked).Error
+}
+
+// _stockShortages reports the products of demand short of stock, keyed by
+// product, locking them first with lock. Stock reserved by other buyers'
+// checkouts is off limits.
+func _stockShortages(tx *gorm.DB, demand map[uint64]int, buyerID uint64, lock bool) (map[uint64]string, error) {
+	shortages := make(map[uint64]string)
+	for _, productID := range _demandProductIDs(demand) {
+		query := tx.Select("id", "name", "stock")
+		if lock {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+
+		var product models.Product
+		err := query.First(&product, productID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			shortages[productID] = fmt.Sprintf("product not found: %d", productID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		reserved, err := models.ReservedStock(tx, product.ID, buyerID)
+		if err != nil {
+			return nil, err
+		}
+		if product.Stock-reserved < demand[productID] {
+			shortages[productID] = fmt.Sprintf("insufficient stock for product: %s", product.Name)
+		}
+	}
+	return shortages, nil
+}
+
+// _demandProductIDs lists the products of demand in id order, the order rows are locked in
+func _demandProductIDs(demand map[uint64]int) []uint64 {
+	productIDs := make([]uint64, 0, len(demand))
+	for productID := range demand {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+	return productIDs
+}
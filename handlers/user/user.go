@@ -27,6 +27,7 @@ var jwtSecretKey = []byte(jwtSecret)
 // @Accept json
 // @Produce json
 // @Param user body CreateUserRequest true "User registration data"
+// @Param X-Cart-Token header string false "Guest cart token, the guest cart is merged into the new buyer's cart"
 // @Success 200 {object} helper.SuccessResponse{data=SignInResponse} "User Created successfully"
 // @Failure 400 {object} helper.ErrorResponse "Bad Request: Invalid input/Email is already registered"
 // @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
@@ -94,6 +95,9 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	// Carry over what the user put in the cart before registering
+	_claimGuestCart(c, uint64(user.ID))
+
 	// Return success response
 	helper.SendSuccess(c, http.StatusOK, "User Created successfully", gin.H{
 		"Token": token,
@@ -107,6 +111,7 @@ func CreateUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user body SignInRequest true "User sign-in data"
+// @Param X-Cart-Token header string false "Guest cart token, the guest cart is merged into the buyer's cart"
 // @Success 200 {object} helper.SuccessResponse{data=SignInResponse} "User Login successfully"
 // @Failure 400 {object} helper.ErrorResponse  "Bad Request: Invalid input"
 // @Failure 500 {object} helper.ErrorResponse  "Internal Server Error"
@@ -149,6 +154,11 @@ func SignIn(c *gin.Context) {
 		return
 	}
 
+	// Carry over what the buyer put in the cart before signing in
+	if user.Role == "buyer" {
+		_claimGuestCart(c, uint64(user.ID))
+	}
+
 	// Return success response with JWT token
 	helper.SendSuccess(c, http.StatusOK, "User Login successfully", gin.H{
 		"Token": token,
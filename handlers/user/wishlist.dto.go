@@ -0,0 +1,22 @@
+package user
+
+type AddToWishlistRequest struct {
+	ProductID uint64 `json:"product_id" binding:"required"` // ID of the product
+}
+
+type MoveToCartRequest struct {
+	Quantity int `json:"quantity" binding:"omitempty,gt=0" example:"1"` // Defaults to 1
+}
+
+type WishlistItemResponse struct {
+	ID          uint64  `json:"id"`
+	ProductID   uint64  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	ImageURL    string  `json:"image_url"`
+	Price       float64 `json:"price"`
+	Stock       int     `json:"stock"`
+	InStock     bool    `json:"in_stock"`
+	CreatedAt   string  `json:"created_at"`
+
+	CompareAtPrice *float64 `json:"compare_at_price"` // Regular price while a sale is running
+}
@@ -0,0 +1,252 @@
+package user
+
+import (
+	"deketna/config"
+	"deketna/helper"
+	"deketna/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddToWishlist saves a product to the buyer's wishlist
+// @Summary Add Product to Wishlist
+// @Description Save a product for later without putting it in the cart. Adding a product twice has no effect.
+// @Tags Wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body AddToWishlistRequest true "Product ID"
+// @Success 200 {object} helper.SuccessResponse "Product added to wishlist successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 404 {object} helper.ErrorResponse "Product not found"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /wishlist [post]
+func AddToWishlist(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	var req AddToWishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+		return
+	}
+
+	var product models.Product
+	if err := config.DB.Scopes(models.PublicProducts).Select("id", "name").First(&product, req.ProductID).Error; err != nil {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not found"})
+		return
+	}
+
+	item := models.WishlistItem{BuyerID: buyerID, ProductID: product.ID}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&item).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to add product to wishlist"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product added to wishlist successfully", gin.H{
+		"product_id": product.ID,
+		"product":    product.Name,
+	})
+}
+
+// GetWishlist lists the buyer's wishlist with current price and stock
+// @Summary Get Wishlist
+// @Description Retrieve the buyer's wishlist with the current price and available stock of every product
+// @Tags Wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 25)"
+// @Success 200 {object} helper.PaginationResponse{data=[]WishlistItemResponse} "List of wishlist items"
+// @Failure 500 {object} helper.ErrorResponse "Failed to retrieve wishlist"
+// @Router /wishlist [get]
+func GetWishlist(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	// Products that were archived or unpublished since are left out
+	query := config.DB.Table("wishlist_items").
+		Joins("JOIN products ON products.id = wishlist_items.product_id").
+		Where("wishlist_items.buyer_id = ?", buyerID).
+		Scopes(models.PublicProducts)
+
+	var totalItems int64
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve wishlist"})
+		return
+	}
+
+	var items []WishlistItemResponse
+	err = query.
+		Select(`
+			wishlist_items.id,
+			wishlist_items.product_id,
+			wishlist_items.created_at,
+
+			products.name AS product_name,
+			products.image_url,
+			` + models.EffectivePriceSQL + ` AS price,
+			` + models.CompareAtPriceSQL + ` AS compare_at_price,
+			` + models.AvailableStockSQL + ` AS stock,
+			` + models.AvailableStockSQL + ` > 0 AS in_stock`).
+		Joins(models.ActiveSaleJoin).
+		Order("wishlist_items.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&items).Error
+
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to retrieve wishlist"})
+		return
+	}
+
+	totalPages := (int(totalItems) + limit - 1) / limit
+	pagination := helper.PaginationMetadata{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: int(totalItems),
+		TotalPages: totalPages,
+		IsNext:     page < totalPages,
+		IsPrev:     page > 1,
+	}
+
+	helper.SendPagination(c, http.StatusOK, "Wishlist retrieved successfully", items, pagination)
+}
+
+// RemoveFromWishlist removes a product from the buyer's wishlist
+// @Summary Remove Product from Wishlist
+// @Description Remove a product from the buyer's wishlist
+// @Tags Wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} helper.SuccessResponse "Product removed from wishlist successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} helper.ErrorResponse "Product not in wishlist"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /wishlist/{product_id} [delete]
+func RemoveFromWishlist(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	result := config.DB.Where("buyer_id = ? AND product_id = ?", buyerID, productID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to remove product from wishlist"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not in wishlist"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product removed from wishlist successfully", nil)
+}
+
+// MoveWishlistItemToCart moves a wishlist product into the cart
+// @Summary Move Wishlist Item to Cart
+// @Description Add a wishlist product to the cart and remove it from the wishlist in one call
+// @Tags Wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Param payload body MoveToCartRequest false "Quantity to put in the cart (default: 1)"
+// @Success 200 {object} helper.SuccessResponse "Product moved to cart successfully"
+// @Failure 400 {object} helper.ErrorResponse "Invalid input data"
+// @Failure 404 {object} helper.ErrorResponse "Product not in wishlist"
+// @Failure 500 {object} helper.ErrorResponse "Internal Server Error"
+// @Router /wishlist/{product_id}/move-to-cart [post]
+func MoveWishlistItemToCart(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+	buyerID := uint64(claims["userid"].(float64)) // Extract Buyer ID
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, []string{"Invalid product ID"})
+		return
+	}
+
+	// The body is optional, an empty one moves a single unit
+	var req MoveToCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helper.SendError(c, http.StatusBadRequest, []string{"Invalid input data", err.Error()})
+			return
+		}
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	var cartItem *models.CartItem
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var wishlistItem models.WishlistItem
+		if err := tx.Where("buyer_id = ? AND product_id = ?", buyerID, productID).First(&wishlistItem).Error; err != nil {
+			return err
+		}
+
+		var product models.Product
+		if err := tx.Scopes(models.PublicProducts).First(&product, productID).Error; err != nil {
+			return err
+		}
+
+		cart, err := _getOrCreateCart(tx, buyerID)
+		if err != nil {
+			return err
+		}
+
+		if cartItem, err = _addCartItem(tx, cart.ID, product, req.Quantity); err != nil {
+			return err
+		}
+
+		return tx.Delete(&wishlistItem).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		helper.SendError(c, http.StatusNotFound, []string{"Product not in wishlist"})
+		return
+	}
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, []string{"Failed to move product to cart"})
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Product moved to cart successfully", gin.H{
+		"cart_id":      cartItem.CartID,
+		"cart_item_id": cartItem.ID,
+		"product_id":   productID,
+		"quantity":     cartItem.Quantity,
+	})
+}
+
+// _isWishlisted reports whether the product is in the user's wishlist
+func _isWishlisted(db *gorm.DB, buyerID, productID uint64) (bool, error) {
+	var count int64
+	err := db.Model(&models.WishlistItem{}).
+		Where("buyer_id = ? AND product_id = ?", buyerID, productID).
+		Count(&count).Error
+	return count > 0, err
+}
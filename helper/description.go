@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// Supported product description formats
+const (
+	DescriptionFormatMarkdown = "markdown"
+	DescriptionFormatHTML     = "html"
+)
+
+var descriptionPolicy = bluemonday.UGCPolicy()
+
+// RenderDescription converts a markdown or HTML product description into
+// HTML that is safe to embed in the storefront. Markdown is rendered first,
+// then the result goes through the same sanitizer as raw HTML.
+func RenderDescription(source, format string) (string, error) {
+	switch format {
+	case "", DescriptionFormatMarkdown:
+		var buf bytes.Buffer
+		if err := goldmark.Convert([]byte(source), &buf); err != nil {
+			return "", fmt.Errorf("failed to render markdown: %v", err)
+		}
+		return descriptionPolicy.Sanitize(buf.String()), nil
+	case DescriptionFormatHTML:
+		return descriptionPolicy.Sanitize(source), nil
+	default:
+		return "", fmt.Errorf("unsupported description format: %s", format)
+	}
+}
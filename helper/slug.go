@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength keeps slugs short enough for URLs, leaving room for a numeric suffix
+const MaxSlugLength = 80
+
+// Slugify turns a name into a lowercase, hyphen separated URL segment.
+// Accents are dropped ("Café" becomes "cafe") and anything that is not a
+// latin letter or digit separates words. The result can be empty.
+func Slugify(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(stripped) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingHyphen = false
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"deketna/config"
+	"deketna/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sweepGuestCarts deletes guest carts nobody touched within the guest cart TTL
+func sweepGuestCarts(db *gorm.DB) error {
+	cutoff := time.Now().Add(-config.GuestCartTTL())
+	abandoned := db.Model(&models.Cart{}).Select("id").
+		Where("buyer_id IS NULL AND updated_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM cart_items WHERE cart_items.cart_id = carts.id AND cart_items.updated_at >= ?)", cutoff)
+
+	var swept int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id IN (?)", abandoned).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN (?)", abandoned).Delete(&models.Cart{})
+		swept = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if swept > 0 {
+		log.Printf("guest cart sweeper: deleted %d abandoned guest carts", swept)
+	}
+	return nil
+}
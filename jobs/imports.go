@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"deketna/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// importStallTimeout is how long an import job can go without saving progress
+// before it is taken for dead. Running imports save every few rows.
+const importStallTimeout = 15 * time.Minute
+
+// failStalledImports fails the import jobs whose runner went away, as when the
+// server restarted halfway, so they don't show as running forever
+func failStalledImports(db *gorm.DB) error {
+	result := db.Model(&models.ProductImportJob{}).
+		Where("status IN ? AND updated_at < ?",
+			[]string{models.ImportJobPending, models.ImportJobRunning}, time.Now().Add(-importStallTimeout)).
+		Updates(map[string]interface{}{
+			"status":      models.ImportJobFailed,
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Printf("import reaper: failed %d stalled import jobs", result.RowsAffected)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Start launches the background jobs of the application
+func Start(db *gorm.DB) {
+	go every("reservation sweeper", time.Minute, func() error {
+		return sweepReservations(db)
+	})
+	go every("publisher", time.Minute, func() error {
+		return publishScheduledProducts(db)
+	})
+	go every("recommendations", 10*time.Minute, func() error {
+		return updateRecommendations(db)
+	})
+	go every("upload sweeper", 10*time.Minute, func() error {
+		return expireUploads(db)
+	})
+	go every("media gc", time.Hour, func() error {
+		return collectOrphanedMedia(db)
+	})
+	go every("guest cart sweeper", time.Hour, func() error {
+		return sweepGuestCarts(db)
+	})
+	go every("import reaper", 5*time.Minute, func() error {
+		return failStalledImports(db)
+	})
+}
+
+// every runs fn right away and then on every tick of interval, logging failures
+func every(name string, interval time.Duration, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(); err != nil {
+			log.Printf("%s: %v", name, err)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"deketna/config"
+	"deketna/models"
+	"deketna/storage"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const mediaGCBatchSize = 100
+
+// collectOrphanedMedia deletes stored objects that no record has referenced
+// for the grace period. In dry-run mode they are only reported.
+func collectOrphanedMedia(db *gorm.DB) error {
+	orphaned, err := models.MarkOrphanedMedia(db)
+	if err != nil {
+		return err
+	}
+	if orphaned > 0 {
+		log.Printf("media gc: %d objects lost their last reference", orphaned)
+	}
+
+	cutoff := time.Now().Add(-config.MediaGCGrace())
+	if config.MediaGCDryRun() {
+		objects, err := models.OrphanedMedia(db, cutoff, -1)
+		if err != nil {
+			return err
+		}
+		var size int64
+		for _, object := range objects {
+			log.Printf("media gc: would delete %s (%d bytes, orphaned since %s)", object.Key, object.Size, object.OrphanedAt.Format(time.RFC3339))
+			size += object.Size
+		}
+		if len(objects) > 0 {
+			log.Printf("media gc: dry run, %d objects (%d bytes) would be deleted", len(objects), size)
+		}
+		return nil
+	}
+
+	deleted := 0
+	// Objects that fail to delete are skipped for the rest of the run and
+	// retried on the next one, so they don't hold up the others
+	var failed []uint64
+	for {
+		query := db
+		if len(failed) > 0 {
+			query = db.Where("id NOT IN ?", failed)
+		}
+		objects, err := models.OrphanedMedia(query, cutoff, mediaGCBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range objects {
+			// The record stays locked until the object is gone, so an upload
+			// of the same content waits and then stores it again
+			err := db.Transaction(func(tx *gorm.DB) error {
+				forgotten, err := models.ForgetOrphanedMedia(tx, object.ID, cutoff)
+				if err != nil || !forgotten {
+					return err
+				}
+				if err := storage.Delete(object.Key); err != nil {
+					return err
+				}
+				deleted++
+				return nil
+			})
+			if err != nil {
+				log.Printf("media gc: failed to delete %s: %v", object.Key, err)
+				failed = append(failed, object.ID)
+			}
+		}
+
+		if len(objects) < mediaGCBatchSize {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		log.Printf("media gc: deleted %d orphaned objects", deleted)
+	}
+	if len(failed) > 0 {
+		log.Printf("media gc: %d orphaned objects could not be deleted, retrying next run", len(failed))
+	}
+	return nil
+}
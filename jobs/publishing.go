@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"deketna/models"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// publishScheduledProducts moves scheduled products whose publish time has
+// come to published. They are public from that time on already, publishing
+// records when they went live.
+func publishScheduledProducts(db *gorm.DB) error {
+	published, err := models.PublishScheduledProducts(db)
+	if err != nil {
+		return err
+	}
+	if published > 0 {
+		log.Printf("publisher: published %d scheduled products", published)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"deketna/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const recommendationBatchSize = 500
+
+// updateRecommendations folds finished orders that haven't been counted yet
+// into the product affinity and sales statistics, batch by batch, so a run
+// only reads orders that finished since the previous one
+func updateRecommendations(db *gorm.DB) error {
+	total := 0
+	for {
+		processed, err := _processRecommendationBatch(db)
+		if err != nil {
+			return err
+		}
+		total += processed
+		if processed < recommendationBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("recommendations: counted %d new orders", total)
+	}
+	return nil
+}
+
+func _processRecommendationBatch(db *gorm.DB) (int, error) {
+	var orderIDs []uint64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Skip locked rows so two instances never count the same orders
+		if err := tx.Raw(`
+			SELECT orders.id FROM orders
+			WHERE orders.status = 'finish'
+				AND NOT EXISTS (
+					SELECT 1 FROM recommendation_processed_orders
+					WHERE recommendation_processed_orders.order_id = orders.id)
+			ORDER BY orders.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`, recommendationBatchSize).
+			Scan(&orderIDs).Error; err != nil {
+			return err
+		}
+		if len(orderIDs) == 0 {
+			return nil
+		}
+
+		// Every pair of distinct products in an order, in both directions.
+		// Components of bundles weren't picked by the buyer and are left out.
+		if err := tx.Exec(`
+			INSERT INTO product_affinities (product_id, related_product_id, score, updated_at)
+			SELECT a.product_id, b.product_id, COUNT(DISTINCT a.order_id), NOW()
+			FROM order_items a
+			JOIN order_items b ON b.order_id = a.order_id AND b.product_id <> a.product_id AND b.bundle_item_id IS NULL
+			WHERE a.order_id IN ? AND a.bundle_item_id IS NULL
+			GROUP BY a.product_id, b.product_id
+			ON CONFLICT (product_id, related_product_id)
+			DO UPDATE SET score = product_affinities.score + EXCLUDED.score, updated_at = NOW()`, orderIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO product_sales_stats (product_id, units_sold, updated_at)
+			SELECT order_items.product_id, SUM(order_items.quantity), NOW()
+			FROM order_items
+			WHERE order_items.order_id IN ? AND order_items.bundle_item_id IS NULL
+			GROUP BY order_items.product_id
+			ON CONFLICT (product_id)
+			DO UPDATE SET units_sold = product_sales_stats.units_sold + EXCLUDED.units_sold, updated_at = NOW()`, orderIDs).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		processed := make([]models.RecommendationProcessedOrder, len(orderIDs))
+		for i, orderID := range orderIDs {
+			processed[i] = models.RecommendationProcessedOrder{OrderID: orderID, ProcessedAt: now}
+		}
+		return tx.Omit("Order").Create(&processed).Error
+	})
+	return len(orderIDs), err
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"deketna/models"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// sweepReservations expires checkout reservations past their TTL. Expired
+// reservations already stop holding stock, sweeping keeps their status honest.
+func sweepReservations(db *gorm.DB) error {
+	swept, err := models.ExpireStockReservations(db)
+	if err != nil {
+		return err
+	}
+	if swept > 0 {
+		log.Printf("reservation sweeper: expired %d reservations", swept)
+	}
+	return nil
+}
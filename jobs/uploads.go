@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"deketna/models"
+	"deketna/storage"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// uploadConfirmWindow is how long after its URL expired a direct upload can
+// still be confirmed
+const uploadConfirmWindow = time.Hour
+
+// expireUploads removes the files of direct uploads that were never confirmed
+// and expires confirmed uploads past their hold
+func expireUploads(db *gorm.DB) error {
+	var uploads []models.Upload
+	if err := db.Where("status = ? AND expires_at < ?", models.UploadPending, time.Now().Add(-uploadConfirmWindow)).
+		Find(&uploads).Error; err != nil {
+		return err
+	}
+
+	expired := 0
+	for _, upload := range uploads {
+		// The upload stays locked until its file is gone, so a late
+		// confirmation either wins or finds it expired
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&upload).
+				Where("status = ?", models.UploadPending).
+				Update("status", models.UploadExpired)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			if err := storage.Delete(upload.StagingKey); err != nil {
+				return err
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if expired > 0 {
+		log.Printf("upload sweeper: expired %d unconfirmed uploads", expired)
+	}
+
+	// Confirmed uploads no product claimed in time let go of their renditions,
+	// media garbage collection takes it from there
+	result := db.Model(&models.Upload{}).
+		Where("status = ? AND expires_at < ?", models.UploadConfirmed, time.Now()).
+		Update("status", models.UploadExpired)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("upload sweeper: expired %d unclaimed uploads", result.RowsAffected)
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"deketna/config"
+	"deketna/jobs"
 	"deketna/middleware"
+	"deketna/notification"
 	"deketna/router"
+	"deketna/storage"
 	"log"
+	"net/http"
 	"os"
 
 	_ "deketna/docs" // Import Swagger docs
@@ -46,10 +50,24 @@ func main() {
 	// Connect to database
 	config.ConnectDB()
 
+	// Pick the channel for admin notifications
+	notification.Init()
+
+	// Pick where uploads are stored
+	storage.Init()
+
+	// Start background jobs such as the reservation sweeper
+	jobs.Start(config.DB)
+
 	// Set up router
 	r := gin.Default()
-	r.Static("/uploads", "./uploads")
 	r.Use(middleware.CORSMiddleware())
+	if local, ok := storage.Current().(*storage.LocalStorage); ok {
+		r.Static("/uploads", local.Dir)
+		// Direct uploads PUT their file to a signed URL under /uploads
+		upload := http.MaxBytesHandler(local.UploadHandler(), config.MaxImageUploadBytes())
+		r.PUT("/uploads/*filepath", gin.WrapH(http.StripPrefix("/uploads", upload)))
+	}
 
 	r.SetTrustedProxies(nil)
 	// Routes
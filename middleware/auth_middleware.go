@@ -165,3 +165,31 @@ func SignInMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalSignInMiddleware stores the claims when a valid token is sent but
+// lets anonymous requests through, for public routes that personalise their response
+func OptionalSignInMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" || tokenString == c.GetHeader("Authorization") {
+			c.Next()
+			return
+		}
+
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("invalid signing method")
+			}
+			return jwtSecretKey, nil
+		})
+
+		// An invalid token is treated as an anonymous request
+		if err == nil && token.Valid {
+			if claims, ok := token.Claims.(jwt.MapClaims); ok {
+				c.Set("claims", claims)
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -1,7 +1,14 @@
 package models
 
 import (
+	"database/sql"
+	"deketna/helper"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
@@ -30,7 +37,9 @@ type Profile struct {
 
 type Product struct {
 	ID         uint64    `gorm:"primaryKey" json:"id"`
+	SKU        string    `gorm:"size:64;uniqueIndex:idx_products_sku,where:sku <> ''" json:"sku"` // Stock keeping unit, unique when set
 	Name       string    `json:"name"`
+	Slug       string    `gorm:"size:96;not null;default:'';uniqueIndex:idx_products_slug,where:slug <> ''" json:"slug"` // Derived from the name, see AssignProductSlug
 	Price      float64   `json:"price"`
 	Stock      int       `json:"stock"`
 	SellerID   uint64    `json:"seller_id"`
@@ -39,9 +48,201 @@ type Product struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
+	// Lifecycle: archived products are soft deleted so order history keeps them
+	Status    string         `gorm:"size:16;not null;default:'draft';index" json:"status"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Publishing: a scheduled product goes live at PublishAt, PublishedAt is
+	// when it last went live
+	PublishAt   *time.Time `gorm:"index" json:"publish_at"`
+	PublishedAt *time.Time `json:"published_at"`
+
+	// Bundles sell a set of other products, see BundleComponent
+	Type string `gorm:"size:16;not null;default:'simple'" json:"type"`
+
+	// Version goes up with every change an admin could have been looking at,
+	// so edits based on an outdated copy are refused
+	Version int `gorm:"not null;default:1" json:"version"`
+
+	// Content
+	Description       string  `gorm:"type:text" json:"description"`                         // Markdown or HTML source as entered by the admin
+	DescriptionFormat string  `gorm:"size:16;default:'markdown'" json:"description_format"` // markdown or html
+	DescriptionHTML   string  `gorm:"type:text" json:"description_html"`                    // Sanitized HTML rendered from Description
+	Brand             string  `gorm:"size:255;index" json:"brand"`
+	WeightGrams       float64 `json:"weight_grams"`
+	LengthCM          float64 `json:"length_cm"`
+	WidthCM           float64 `json:"width_cm"`
+	HeightCM          float64 `json:"height_cm"`
+
+	// Review aggregates over visible reviews, kept up to date by RefreshProductRating
+	RatingAverage float64 `gorm:"not null;default:0" json:"rating_average"`
+	RatingCount   int     `gorm:"not null;default:0" json:"rating_count"`
+
+	// Admins are alerted once when stock falls to the threshold or below, 0 disables alerts.
+	// LowStockAlertedAt suppresses further alerts until the product is restocked above it.
+	ReorderThreshold  int        `gorm:"not null;default:0" json:"reorder_threshold"`
+	LowStockAlertedAt *time.Time `json:"low_stock_alerted_at"`
+
 	// Relationships
-	Seller   User      `gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE" json:"seller"`
-	Category *Category `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"category"`
+	Seller     User               `gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE" json:"seller"`
+	Category   *Category          `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"category"`
+	Images     []ProductImage     `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"images"`
+	Attributes []ProductAttribute `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"attributes"`
+}
+
+// Product lifecycle states
+const (
+	ProductStatusDraft       = "draft"
+	ProductStatusScheduled   = "scheduled"
+	ProductStatusPublished   = "published"
+	ProductStatusUnpublished = "unpublished"
+	ProductStatusArchived    = "archived"
+)
+
+// Product types
+const (
+	ProductTypeSimple = "simple"
+	ProductTypeBundle = "bundle"
+)
+
+// BundleComponent is a product contained in a bundle, Quantity times per
+// bundle. Bundles hold no stock of their own: selling one takes the stock of
+// its components.
+type BundleComponent struct {
+	ID          uint64 `gorm:"primaryKey" json:"id"`
+	BundleID    uint64 `gorm:"not null;uniqueIndex:idx_bundle_components_bundle_component" json:"bundle_id"`
+	ComponentID uint64 `gorm:"not null;uniqueIndex:idx_bundle_components_bundle_component;index" json:"component_id"`
+	Quantity    int    `gorm:"not null" json:"quantity"`
+
+	Bundle    Product `gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE" json:"-"`
+	Component Product `gorm:"foreignKey:ComponentID;constraint:OnDelete:RESTRICT" json:"-"`
+}
+
+// StockComponents lists the products whose stock one unit of product takes:
+// the components of a bundle, or the product itself
+func StockComponents(tx *gorm.DB, product Product) ([]BundleComponent, error) {
+	if product.Type != ProductTypeBundle {
+		return []BundleComponent{{BundleID: product.ID, ComponentID: product.ID, Quantity: 1}}, nil
+	}
+
+	var components []BundleComponent
+	if err := tx.Where("bundle_id = ?", product.ID).Order("component_id").Find(&components).Error; err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, ErrEmptyBundle
+	}
+	return components, nil
+}
+
+// ErrEmptyBundle is returned for a bundle without components, which can't be sold
+var ErrEmptyBundle = errors.New("bundle has no components")
+
+// PublicProducts limits a products query to what buyers may see and order.
+// Scheduled products are public from their publish time on, even before the
+// scheduler gets to them.
+func PublicProducts(db *gorm.DB) *gorm.DB {
+	return db.Where(
+		"products.deleted_at IS NULL AND (products.status = ? OR (products.status = ? AND products.publish_at <= NOW()))",
+		ProductStatusPublished, ProductStatusScheduled,
+	)
+}
+
+// IsPublic is PublicProducts for a loaded product
+func (p Product) IsPublic() bool {
+	if p.DeletedAt.Valid {
+		return false
+	}
+	return p.Status == ProductStatusPublished ||
+		(p.Status == ProductStatusScheduled && p.PublishAt != nil && !p.PublishAt.After(time.Now()))
+}
+
+// PublishScheduledProducts publishes the scheduled products whose publish
+// time has come and returns how many went live
+func PublishScheduledProducts(db *gorm.DB) (int64, error) {
+	result := db.Model(&Product{}).
+		Where("status = ? AND publish_at <= NOW()", ProductStatusScheduled).
+		UpdateColumns(map[string]interface{}{
+			"status":       ProductStatusPublished,
+			"published_at": gorm.Expr("publish_at"),
+			"publish_at":   nil,
+			"version":      gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ProductSlugRedirect keeps a slug a product had before it was renamed, so
+// old links keep working
+type ProductSlugRedirect struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"size:96;not null;uniqueIndex" json:"slug"`
+	ProductID uint64    `gorm:"not null;index" json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// AssignProductSlug gives a saved product a unique slug derived from its
+// name, adding a numeric suffix when the slug is taken. Slugs other products
+// had before are taken too. The previous slug of the product keeps
+// redirecting to it.
+func AssignProductSlug(tx *gorm.DB, product *Product) error {
+	base := helper.Slugify(product.Name)
+	if base == "" {
+		base = "product"
+	}
+
+	var taken []string
+	if err := tx.Raw(`
+		SELECT slug FROM products WHERE id <> ? AND (slug = ? OR slug LIKE ?)
+		UNION
+		SELECT slug FROM product_slug_redirects WHERE product_id <> ? AND (slug = ? OR slug LIKE ?)`,
+		product.ID, base, base+"-%", product.ID, base, base+"-%").
+		Scan(&taken).Error; err != nil {
+		return err
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		takenSet[slug] = true
+	}
+
+	slug := base
+	for n := 2; takenSet[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	if slug == product.Slug {
+		return nil
+	}
+
+	// A product renamed back to an earlier name gets its old slug back
+	if err := tx.Where("slug = ? AND product_id = ?", slug, product.ID).Delete(&ProductSlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if product.Slug != "" {
+		if err := tx.Omit("Product").Create(&ProductSlugRedirect{Slug: product.Slug, ProductID: product.ID}).Error; err != nil {
+			return err
+		}
+	}
+
+	product.Slug = slug
+	return tx.Model(&Product{}).Unscoped().Where("id = ?", product.ID).UpdateColumn("slug", slug).Error
+}
+
+// ProductImage is one entry of a product gallery. Product.ImageURL mirrors the
+// URL of the primary image so listing queries don't need to join this table.
+type ProductImage struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ProductID uint64    `gorm:"index;not null" json:"product_id"`
+	URL       string    `gorm:"not null" json:"url"`
+	AltText   string    `gorm:"size:255" json:"alt_text"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	IsPrimary bool      `gorm:"not null;default:false" json:"is_primary"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Smaller renditions of the image, URL holds the large one
+	MediumURL    string `gorm:"not null;default:''" json:"medium_url"`
+	ThumbnailURL string `gorm:"not null;default:''" json:"thumbnail_url"`
 }
 
 type Category struct {
@@ -52,7 +253,650 @@ type Category struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 
 	// One-to-many relationship
-	Products []Product `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"products"`
+	Products   []Product           `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"products"`
+	Attributes []CategoryAttribute `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE" json:"attributes"`
+}
+
+// Attribute value types accepted by CategoryAttribute.Type
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+// CategoryAttribute defines one specification field that products of a
+// category may (or must) fill in.
+type CategoryAttribute struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CategoryID uint      `gorm:"not null;uniqueIndex:idx_category_attribute_key" json:"category_id"`
+	Key        string    `gorm:"size:100;not null;uniqueIndex:idx_category_attribute_key" json:"key"`
+	Label      string    `gorm:"size:255;not null" json:"label"`
+	Type       string    `gorm:"size:16;not null;default:'text'" json:"type"`
+	Options    string    `gorm:"type:text" json:"options"` // Comma separated allowed values for enum attributes
+	Unit       string    `gorm:"size:32" json:"unit"`
+	Required   bool      `gorm:"not null;default:false" json:"required"`
+	Position   int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProductAttribute is a specification value of a product, keyed by
+// CategoryAttribute.Key of the product's category.
+type ProductAttribute struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ProductID uint64    `gorm:"not null;uniqueIndex:idx_product_attribute_key" json:"product_id"`
+	Key       string    `gorm:"size:100;not null;uniqueIndex:idx_product_attribute_key;index" json:"key"`
+	Value     string    `gorm:"type:text;not null" json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductRevision is a snapshot of a product taken after every change an
+// admin made to it, numbered from 1 per product
+type ProductRevision struct {
+	ID           uint64    `gorm:"primaryKey" json:"id"`
+	ProductID    uint64    `gorm:"not null;uniqueIndex:idx_product_revisions_product_revision" json:"product_id"`
+	Revision     int       `gorm:"not null;uniqueIndex:idx_product_revisions_product_revision" json:"revision"`
+	Action       string    `gorm:"size:16;not null" json:"action"`
+	Snapshot     string    `gorm:"type:jsonb;not null" json:"snapshot"` // ProductSnapshot as JSON
+	ActorID      *uint64   `gorm:"index" json:"actor_id"`
+	RestoredFrom *int      `json:"restored_from"` // Revision a rollback went back to
+	CreatedAt    time.Time `json:"created_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+	Actor   *User   `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// What led to a product revision
+const (
+	RevisionBaseline   = "baseline" // State of the product when revisions were introduced
+	RevisionCreated    = "created"
+	RevisionUpdated    = "updated"
+	RevisionImported   = "imported"
+	RevisionArchived   = "archived"
+	RevisionUnarchived = "unarchived"
+	RevisionRolledBack = "rolled_back"
+)
+
+// ProductSnapshot holds the fields of a product admins edit. Stock is left
+// out, the inventory ledger keeps its history.
+type ProductSnapshot struct {
+	SKU               string            `json:"sku"`
+	Name              string            `json:"name"`
+	Price             float64           `json:"price"`
+	CategoryID        *uint             `json:"category_id"`
+	Status            string            `json:"status"`
+	PublishAt         *time.Time        `json:"publish_at"`
+	Description       string            `json:"description"`
+	DescriptionFormat string            `json:"description_format"`
+	Brand             string            `json:"brand"`
+	WeightGrams       float64           `json:"weight_grams"`
+	LengthCM          float64           `json:"length_cm"`
+	WidthCM           float64           `json:"width_cm"`
+	HeightCM          float64           `json:"height_cm"`
+	ReorderThreshold  int               `json:"reorder_threshold"`
+	Attributes        map[string]string `json:"attributes"`
+}
+
+// RecordProductRevision snapshots the product as it is in tx, so it belongs
+// at the end of the transaction making the change
+func RecordProductRevision(tx *gorm.DB, productID uint64, action string, actorID *uint64, restoredFrom *int) error {
+	var product Product
+	if err := tx.Unscoped().First(&product, productID).Error; err != nil {
+		return err
+	}
+	var attributes []ProductAttribute
+	if err := tx.Where("product_id = ?", productID).Find(&attributes).Error; err != nil {
+		return err
+	}
+
+	snapshot := ProductSnapshot{
+		SKU:               product.SKU,
+		Name:              product.Name,
+		Price:             product.Price,
+		CategoryID:        product.CategoryID,
+		Status:            product.Status,
+		PublishAt:         product.PublishAt,
+		Description:       product.Description,
+		DescriptionFormat: product.DescriptionFormat,
+		Brand:             product.Brand,
+		WeightGrams:       product.WeightGrams,
+		LengthCM:          product.LengthCM,
+		WidthCM:           product.WidthCM,
+		HeightCM:          product.HeightCM,
+		ReorderThreshold:  product.ReorderThreshold,
+		Attributes:        make(map[string]string, len(attributes)),
+	}
+	for _, attribute := range attributes {
+		snapshot.Attributes[attribute.Key] = attribute.Value
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var last int
+	if err := tx.Model(&ProductRevision{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&ProductRevision{
+		ProductID:    productID,
+		Revision:     last + 1,
+		Action:       action,
+		Snapshot:     string(data),
+		ActorID:      actorID,
+		RestoredFrom: restoredFrom,
+	}).Error
+}
+
+// ProductReview is a rating left by a buyer who received the product.
+// A buyer can review a product once.
+type ProductReview struct {
+	ID           uint64     `gorm:"primaryKey" json:"id"`
+	ProductID    uint64     `gorm:"not null;uniqueIndex:idx_product_review_buyer" json:"product_id"`
+	BuyerID      uint64     `gorm:"not null;uniqueIndex:idx_product_review_buyer;index" json:"buyer_id"`
+	OrderID      uint64     `gorm:"not null" json:"order_id"` // Finished order that proves the purchase
+	Rating       int        `gorm:"not null;check:rating BETWEEN 1 AND 5" json:"rating"`
+	Body         string     `gorm:"type:text" json:"body"`
+	HelpfulCount int        `gorm:"not null;default:0" json:"helpful_count"`
+	IsHidden     bool       `gorm:"not null;default:false;index" json:"is_hidden"`
+	HiddenReason string     `gorm:"size:255" json:"hidden_reason"`
+	HiddenAt     *time.Time `json:"hidden_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Product Product              `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+	Buyer   User                 `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE" json:"-"`
+	Photos  []ProductReviewPhoto `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"photos"`
+}
+
+type ProductReviewPhoto struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ReviewID  uint64    `gorm:"not null;index" json:"review_id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Smaller renditions of the photo, URL holds the large one
+	MediumURL    string `gorm:"not null;default:''" json:"medium_url"`
+	ThumbnailURL string `gorm:"not null;default:''" json:"thumbnail_url"`
+}
+
+// ReviewHelpfulVote records that a user found a review helpful
+type ReviewHelpfulVote struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ReviewID  uint64    `gorm:"not null;uniqueIndex:idx_review_helpful_vote" json:"review_id"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_review_helpful_vote" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Review ProductReview `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// RefreshProductRating recomputes the rating aggregates of a product from its visible reviews
+func RefreshProductRating(tx *gorm.DB, productID uint64) error {
+	return tx.Exec(`
+		UPDATE products SET
+			rating_average = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM product_reviews WHERE product_id = ? AND is_hidden = false), 0),
+			rating_count = (SELECT COUNT(*) FROM product_reviews WHERE product_id = ? AND is_hidden = false)
+		WHERE id = ?`, productID, productID, productID).Error
+}
+
+// MediaObject is a file kept in storage. Keys are derived from the content,
+// so identical uploads share their objects and never overwrite others.
+type MediaObject struct {
+	ID               uint64    `gorm:"primaryKey" json:"id"`
+	Key              string    `gorm:"size:255;uniqueIndex;not null" json:"key"`
+	SourceHash       string    `gorm:"size:64;index;not null" json:"source_hash"` // SHA-256 of the file as uploaded
+	ContentType      string    `gorm:"size:100;not null" json:"content_type"`
+	Size             int64     `gorm:"not null" json:"size"`
+	OriginalFilename string    `gorm:"size:255" json:"original_filename"` // Kept for reference only
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Set while no record references the object. Garbage collection deletes
+	// it once it has been orphaned for the grace period.
+	OrphanedAt *time.Time `gorm:"index" json:"orphaned_at"`
+}
+
+// MediaReference records that a product image or a review photo uses a media object.
+// References go with the record through ON DELETE CASCADE.
+type MediaReference struct {
+	ID             uint64    `gorm:"primaryKey" json:"id"`
+	MediaObjectID  uint64    `gorm:"not null;index" json:"media_object_id"`
+	ProductImageID *uint64   `gorm:"index" json:"product_image_id,omitempty"`
+	ReviewPhotoID  *uint64   `gorm:"index" json:"review_photo_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	MediaObject  MediaObject         `gorm:"foreignKey:MediaObjectID;constraint:OnDelete:CASCADE" json:"-"`
+	ProductImage *ProductImage       `gorm:"foreignKey:ProductImageID;constraint:OnDelete:CASCADE" json:"-"`
+	ReviewPhoto  *ProductReviewPhoto `gorm:"foreignKey:ReviewPhotoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// unreferencedMediaSQL matches media objects no record uses. A confirmed
+// upload holds the renditions of its content until it expires, so they
+// survive until a product claims them.
+const unreferencedMediaSQL = `(NOT EXISTS (SELECT 1 FROM media_references WHERE media_references.media_object_id = media_objects.id)
+	AND NOT EXISTS (SELECT 1 FROM uploads WHERE uploads.source_hash = media_objects.source_hash
+		AND uploads.status = 'confirmed' AND uploads.expires_at > NOW()))`
+
+// UnreferencedMedia limits a query to media objects no record uses
+func UnreferencedMedia(db *gorm.DB) *gorm.DB {
+	return db.Where(unreferencedMediaSQL)
+}
+
+// ReferenceProductImageMedia points a product image at the media objects
+// stored under keys, replacing the ones it used before
+func ReferenceProductImageMedia(tx *gorm.DB, imageID uint64, keys []string) error {
+	return referenceMedia(tx, "product_image_id", imageID, keys)
+}
+
+// ReferenceReviewPhotoMedia points a review photo at the media objects stored under keys
+func ReferenceReviewPhotoMedia(tx *gorm.DB, photoID uint64, keys []string) error {
+	return referenceMedia(tx, "review_photo_id", photoID, keys)
+}
+
+func referenceMedia(tx *gorm.DB, column string, ownerID uint64, keys []string) error {
+	if err := tx.Where(column+" = ?", ownerID).Delete(&MediaReference{}).Error; err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Objects in use again are no longer on their way to deletion
+	if err := tx.Model(&MediaObject{}).Where("key IN ?", keys).Update("orphaned_at", nil).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO media_references (media_object_id, `+column+`, created_at)
+		SELECT id, ?, NOW() FROM media_objects WHERE key IN ?`, ownerID, keys).Error
+}
+
+// ReuseMedia claims the media objects stored under keys for a new upload of
+// the same content. It reports false unless every one of them is stored.
+func ReuseMedia(db *gorm.DB, keys []string) (bool, error) {
+	result := db.Model(&MediaObject{}).Where("key IN ?", keys).Update("orphaned_at", nil)
+	return result.RowsAffected == int64(len(keys)), result.Error
+}
+
+// MarkOrphanedMedia stamps the media objects that lost their last reference
+// and clears the stamp of the ones referenced again
+func MarkOrphanedMedia(db *gorm.DB) (int64, error) {
+	if err := db.Model(&MediaObject{}).
+		Where("orphaned_at IS NOT NULL AND NOT ("+unreferencedMediaSQL+")").
+		Update("orphaned_at", nil).Error; err != nil {
+		return 0, err
+	}
+	result := db.Model(&MediaObject{}).
+		Where("orphaned_at IS NULL AND "+unreferencedMediaSQL).
+		Update("orphaned_at", gorm.Expr("NOW()"))
+	return result.RowsAffected, result.Error
+}
+
+// OrphanedMedia lists media objects that have been orphaned since before cutoff
+func OrphanedMedia(db *gorm.DB, cutoff time.Time, limit int) ([]MediaObject, error) {
+	var objects []MediaObject
+	err := db.Where("orphaned_at <= ? AND "+unreferencedMediaSQL, cutoff).
+		Order("orphaned_at, id").
+		Limit(limit).
+		Find(&objects).Error
+	return objects, err
+}
+
+// ForgetOrphanedMedia deletes the record of an orphaned media object unless
+// it was referenced or reused in the meantime
+func ForgetOrphanedMedia(tx *gorm.DB, id uint64, cutoff time.Time) (bool, error) {
+	result := tx.Where("id = ? AND orphaned_at <= ? AND "+unreferencedMediaSQL, id, cutoff).Delete(&MediaObject{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Direct upload states
+const (
+	UploadPending   = "pending"   // Waiting for the file to be stored and confirmed
+	UploadConfirmed = "confirmed" // A valid image, its renditions can be used
+	UploadRejected  = "rejected"  // The stored file wasn't a valid image
+	UploadExpired   = "expired"   // Never confirmed, the stored file was removed
+)
+
+// Upload is a file an admin stores straight in storage through a signed URL.
+// Products can only use it once it has been confirmed as a valid image, and
+// only until it expires again.
+type Upload struct {
+	ID               uint64     `gorm:"primaryKey" json:"id"`
+	StagingKey       string     `gorm:"size:255;uniqueIndex;not null" json:"staging_key"` // Where the client stores the file
+	OriginalFilename string     `gorm:"size:255" json:"original_filename"`
+	ContentType      string     `gorm:"size:100;not null" json:"content_type"`
+	Status           string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	SourceHash       string     `gorm:"size:64;index" json:"source_hash"` // Names the renditions once confirmed
+	CreatedBy        uint64     `gorm:"not null" json:"created_by"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"` // When the upload URL stops working, once confirmed when products can no longer use it
+	ConfirmedAt      *time.Time `json:"confirmed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Import job states
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ProductImportJob tracks a bulk product import started from a CSV or XLSX file
+type ProductImportJob struct {
+	ID            uint64     `gorm:"primaryKey" json:"id"`
+	FileName      string     `gorm:"size:255" json:"file_name"`
+	Status        string     `gorm:"size:16;not null;default:'pending';index" json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	CreatedCount  int        `json:"created_count"`
+	UpdatedCount  int        `json:"updated_count"`
+	FailedCount   int        `json:"failed_count"`
+	Errors        string     `gorm:"type:text" json:"errors"` // JSON encoded row errors
+	CreatedBy     uint64     `json:"created_by"`
+	StartedAt     *time.Time `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Stock movement reasons
+const (
+	StockReasonSale         = "sale"
+	StockReasonRestock      = "restock"
+	StockReasonAdjustment   = "adjustment"
+	StockReasonReturn       = "return"
+	StockReasonCancellation = "cancellation"
+)
+
+// ErrInsufficientStock is returned when a movement would take stock below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ErrBundleStock is returned for a movement on a bundle, whose stock is its components'
+var ErrBundleStock = errors.New("bundles hold no stock")
+
+// StockMovement is an immutable entry of the inventory ledger. The stock of a
+// product always equals the sum of the deltas of its movements.
+type StockMovement struct {
+	ID           uint64    `gorm:"primaryKey" json:"id"`
+	ProductID    uint64    `gorm:"not null;index" json:"product_id"`
+	Delta        int       `gorm:"not null" json:"delta"`
+	Reason       string    `gorm:"size:16;not null;index" json:"reason"`
+	ReferenceID  string    `gorm:"size:64;index" json:"reference_id"` // What caused the change, e.g. order:42 or import:7
+	ActorID      *uint64   `json:"actor_id"`                          // Nil for changes made by the system
+	Note         string    `gorm:"size:255" json:"note"`
+	BalanceAfter int       `gorm:"not null" json:"balance_after"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+	Actor   *User   `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// ApplyStockMovement changes the stock of the product by the movement delta
+// and records the movement, so it must run inside the caller's transaction.
+// Stock should never be written any other way.
+func ApplyStockMovement(tx *gorm.DB, movement *StockMovement) error {
+	if movement.Delta == 0 {
+		return nil
+	}
+
+	// Restocking above the reorder threshold re-arms the low-stock alert
+	err := tx.Raw(`
+		UPDATE products SET
+			stock = stock + ?,
+			low_stock_alerted_at = CASE WHEN stock + ? > reorder_threshold THEN NULL ELSE low_stock_alerted_at END
+		WHERE id = ? AND type <> ? AND stock + ? >= 0
+		RETURNING stock`,
+		movement.Delta, movement.Delta, movement.ProductID, ProductTypeBundle, movement.Delta,
+	).Row().Scan(&movement.BalanceAfter)
+	if errors.Is(err, sql.ErrNoRows) {
+		var product Product
+		if err := tx.Unscoped().Select("id", "type").First(&product, movement.ProductID).Error; err != nil {
+			return err
+		}
+		if product.Type == ProductTypeBundle {
+			return ErrBundleStock
+		}
+		return ErrInsufficientStock
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Omit("Product", "Actor").Create(movement).Error
+}
+
+// ProductSalePrice temporarily replaces the price of a product between
+// StartsAt and EndsAt. Sales of the same product never overlap.
+type ProductSalePrice struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ProductID uint64    `gorm:"not null;index" json:"product_id"`
+	SalePrice float64   `gorm:"not null" json:"sale_price"`
+	StartsAt  time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time `gorm:"not null;index" json:"ends_at"`
+	CreatedBy *uint64   `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ActiveSaleJoin joins the sale in effect right now, if any, as active_sale.
+// Use it with EffectivePriceSQL and CompareAtPriceSQL in queries on products.
+const ActiveSaleJoin = `LEFT JOIN LATERAL (
+	SELECT product_sale_prices.sale_price FROM product_sale_prices
+	WHERE product_sale_prices.product_id = products.id
+		AND product_sale_prices.starts_at <= NOW()
+		AND product_sale_prices.ends_at > NOW()
+	ORDER BY product_sale_prices.starts_at DESC
+	LIMIT 1) active_sale ON true`
+
+// EffectivePriceSQL is the price buyers pay right now. A sale never charges
+// more than the regular price, which may have been lowered since the sale
+// was scheduled.
+const EffectivePriceSQL = "LEAST(COALESCE(active_sale.sale_price, products.price), products.price)"
+
+// CompareAtPriceSQL is the regular price while a sale takes it down and NULL otherwise
+const CompareAtPriceSQL = "CASE WHEN active_sale.sale_price < products.price THEN products.price END"
+
+// EffectivePrice returns the price of the product in effect right now, as
+// EffectivePriceSQL does
+func EffectivePrice(tx *gorm.DB, product Product) (float64, error) {
+	var sale ProductSalePrice
+	err := tx.Where("product_id = ? AND starts_at <= ? AND ends_at > ?", product.ID, time.Now(), time.Now()).
+		Order("starts_at DESC").
+		Limit(1).
+		Find(&sale).Error
+	if err != nil {
+		return 0, err
+	}
+	if sale.ID == 0 {
+		return product.Price, nil
+	}
+	return min(sale.SalePrice, product.Price), nil
+}
+
+// Price change types
+const (
+	PriceChangeRegular       = "regular"
+	PriceChangeSale          = "sale"
+	PriceChangeSaleCancelled = "sale_cancelled"
+)
+
+// ProductPriceChange is an entry of the price history of a product. Regular
+// changes record the old and new price, sales record the sale price as the
+// new price with the period it applies to.
+type ProductPriceChange struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	ProductID   uint64     `gorm:"not null;index" json:"product_id"`
+	Type        string     `gorm:"size:16;not null" json:"type"`
+	OldPrice    float64    `gorm:"not null" json:"old_price"`
+	NewPrice    float64    `gorm:"not null" json:"new_price"`
+	SalePriceID *uint64    `json:"sale_price_id"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	ActorID     *uint64    `json:"actor_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// RecordPriceChange adds a regular price change to the history when the price
+// actually changed
+func RecordPriceChange(tx *gorm.DB, productID uint64, oldPrice, newPrice float64, actorID *uint64) error {
+	if oldPrice == newPrice {
+		return nil
+	}
+	return tx.Create(&ProductPriceChange{
+		ProductID: productID,
+		Type:      PriceChangeRegular,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		ActorID:   actorID,
+	}).Error
+}
+
+// ProductAffinity counts the finished orders in which two products were
+// bought together. Every pair is stored in both directions.
+type ProductAffinity struct {
+	ProductID        uint64    `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	RelatedProductID uint64    `gorm:"primaryKey;autoIncrement:false;index" json:"related_product_id"`
+	Score            int       `gorm:"not null;default:0" json:"score"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	Product        Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+	RelatedProduct Product `gorm:"foreignKey:RelatedProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ProductSalesStat holds the units of a product sold in finished orders
+type ProductSalesStat struct {
+	ProductID uint64    `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	UnitsSold int       `gorm:"not null;default:0;index" json:"units_sold"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// RecommendationProcessedOrder marks a finished order as counted in the
+// affinity and sales statistics, so each order is only counted once
+type RecommendationProcessedOrder struct {
+	OrderID     uint64    `gorm:"primaryKey;autoIncrement:false"`
+	ProcessedAt time.Time `gorm:"not null"`
+
+	Order Order `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
+
+// Stock reservation states
+const (
+	ReservationActive    = "active"
+	ReservationConverted = "converted" // Turned into an order
+	ReservationReleased  = "released"  // Checkout cancelled by the buyer
+	ReservationExpired   = "expired"
+)
+
+// StockReservation holds stock for a buyer between checkout and placing the
+// order. Only active reservations that haven't expired hold stock.
+type StockReservation struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ProductID uint64    `gorm:"not null;index:idx_stock_reservations_product_status" json:"product_id"`
+	BuyerID   uint64    `gorm:"not null;index" json:"buyer_id"`
+	Quantity  int       `gorm:"not null" json:"quantity"`
+	Status    string    `gorm:"size:16;not null;default:'active';index:idx_stock_reservations_product_status" json:"status"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	OrderID   *uint64   `json:"order_id"` // Set once converted
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+	Buyer   User    `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ActiveReservations limits a query on stock_reservations to those holding stock
+func ActiveReservations(db *gorm.DB) *gorm.DB {
+	return db.Where("stock_reservations.status = ? AND stock_reservations.expires_at > NOW()", ReservationActive)
+}
+
+// AvailableStockSQL is the on-hand stock of products minus the stock held by
+// active reservations, for use in queries on the products table. A bundle has
+// as many units available as its scarcest component allows, none when a
+// component is archived.
+const AvailableStockSQL = `(CASE WHEN products.type = 'bundle' THEN COALESCE((
+	SELECT MIN(CASE WHEN components.deleted_at IS NULL THEN GREATEST(components.stock - COALESCE((
+		SELECT SUM(stock_reservations.quantity) FROM stock_reservations
+		WHERE stock_reservations.product_id = components.id
+			AND stock_reservations.status = 'active'
+			AND stock_reservations.expires_at > NOW()), 0), 0) / bundle_components.quantity ELSE 0 END)
+	FROM bundle_components
+	JOIN products components ON components.id = bundle_components.component_id
+	WHERE bundle_components.bundle_id = products.id), 0)
+ELSE (products.stock - COALESCE((
+	SELECT SUM(stock_reservations.quantity) FROM stock_reservations
+	WHERE stock_reservations.product_id = products.id
+		AND stock_reservations.status = 'active'
+		AND stock_reservations.expires_at > NOW()), 0)) END)`
+
+// AvailableStock is AvailableStockSQL for a single product
+func AvailableStock(tx *gorm.DB, productID uint64) (int, error) {
+	var available int
+	err := tx.Table("products").
+		Select(AvailableStockSQL).
+		Where("products.id = ?", productID).
+		Scan(&available).Error
+	return available, err
+}
+
+// ReservedStock sums the stock of a product held by active reservations,
+// leaving out those of exceptBuyerID
+func ReservedStock(tx *gorm.DB, productID, exceptBuyerID uint64) (int, error) {
+	var reserved int
+	err := tx.Model(&StockReservation{}).
+		Scopes(ActiveReservations).
+		Where("product_id = ? AND buyer_id <> ?", productID, exceptBuyerID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&reserved).Error
+	return reserved, err
+}
+
+// ExpireStockReservations marks reservations past their expiry as expired and
+// returns how many were swept
+func ExpireStockReservations(db *gorm.DB) (int64, error) {
+	result := db.Model(&StockReservation{}).
+		Where("status = ? AND expires_at <= NOW()", ReservationActive).
+		Update("status", ReservationExpired)
+	return result.RowsAffected, result.Error
+}
+
+// LowStockProduct is a product whose stock reached its reorder threshold
+type LowStockProduct struct {
+	ID               uint64
+	SKU              string
+	Name             string
+	Stock            int
+	ReorderThreshold int
+}
+
+// ClaimLowStockAlerts marks the given products that are at or below their
+// reorder threshold and haven't been alerted yet, and returns them. Marking
+// and returning happen in one statement so concurrent sales alert only once.
+func ClaimLowStockAlerts(tx *gorm.DB, productIDs []uint64) ([]LowStockProduct, error) {
+	var products []LowStockProduct
+	if len(productIDs) == 0 {
+		return products, nil
+	}
+
+	err := tx.Raw(`
+		UPDATE products SET low_stock_alerted_at = NOW()
+		WHERE id IN ?
+			AND reorder_threshold > 0
+			AND stock <= reorder_threshold
+			AND low_stock_alerted_at IS NULL
+		RETURNING id, sku, name, stock, reorder_threshold`, productIDs).
+		Scan(&products).Error
+	return products, err
 }
 
 type AuditLog struct {
@@ -64,12 +908,16 @@ type AuditLog struct {
 	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 }
 
+// Cart belongs to a buyer, or to a guest who holds the token hashed in
+// TokenHash. Guest carts have no buyer until they are merged on sign in.
 type Cart struct {
 	ID        uint64     `gorm:"primaryKey" json:"id"`
-	BuyerID   uint64     `json:"buyer_id"`
+	BuyerID   *uint64    `gorm:"index" json:"buyer_id"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	Items     []CartItem `gorm:"foreignKey:CartID" json:"items"`
+
+	TokenHash *string `gorm:"uniqueIndex" json:"-"`
 }
 
 type CartItem struct {
@@ -79,6 +927,33 @@ type CartItem struct {
 	Quantity  int       `json:"quantity"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Price per unit when the product was put in the cart, to tell the buyer
+	// the price changed since
+	UnitPrice float64 `gorm:"not null;default:0" json:"unit_price"`
+}
+
+// RecentlyViewedProduct is the last time a buyer opened a product's detail page.
+// Each product appears once per user and only the latest views are kept.
+type RecentlyViewedProduct struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_recently_viewed_user_product;index:idx_recently_viewed_user_viewed_at,priority:1" json:"user_id"`
+	ProductID uint64    `gorm:"not null;uniqueIndex:idx_recently_viewed_user_product" json:"product_id"`
+	ViewedAt  time.Time `gorm:"not null;index:idx_recently_viewed_user_viewed_at,priority:2" json:"viewed_at"`
+
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// WishlistItem is a product a buyer saved for later, kept apart from the cart
+type WishlistItem struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	BuyerID   uint64    `gorm:"not null;uniqueIndex:idx_wishlist_buyer_product" json:"buyer_id"`
+	ProductID uint64    `gorm:"not null;uniqueIndex:idx_wishlist_buyer_product;index" json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Buyer   User    `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE" json:"-"`
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"-"`
 }
 
 type Order struct {
@@ -90,6 +965,13 @@ type Order struct {
 	UpdatedAt   time.Time   `json:"updated_at"`
 	Items       []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
 
+	// How the total adds up, as priced by the cart summary when the order was
+	// placed: subtotal - discount + shipping + tax
+	Subtotal float64 `gorm:"not null;default:0" json:"subtotal"`
+	Discount float64 `gorm:"not null;default:0" json:"discount"`
+	Shipping float64 `gorm:"not null;default:0" json:"shipping"`
+	Tax      float64 `gorm:"not null;default:0" json:"tax"`
+
 	Buyer User `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE"`
 }
 
@@ -102,6 +984,11 @@ type OrderItem struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Components of a bundle are ordered as items of their own under the
+	// bundle's item, at no price, so the stock they took is on record
+	BundleItemID *uint64    `gorm:"index" json:"bundle_item_id,omitempty"`
+	BundleItem   *OrderItem `gorm:"foreignKey:BundleItemID;constraint:OnDelete:CASCADE" json:"-"`
+
 	Order   Order   `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Product Product `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"` // Products referenced by orders are archived, never deleted
 }
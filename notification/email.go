@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// EmailChannel sends messages over SMTP to a fixed list of admin addresses
+type EmailChannel struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// NewEmailChannelFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD, SMTP_FROM and NOTIFICATION_EMAILS (comma separated)
+func NewEmailChannelFromEnv() (*EmailChannel, error) {
+	c := &EmailChannel{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+		To:       splitList(os.Getenv("NOTIFICATION_EMAILS")),
+	}
+	if c.Port == "" {
+		c.Port = "587"
+	}
+	if c.Host == "" || c.From == "" {
+		return nil, errors.New("SMTP_HOST and SMTP_FROM are required for email notifications")
+	}
+	if len(c.To) == 0 {
+		return nil, errors.New("NOTIFICATION_EMAILS is required for email notifications")
+	}
+	return c, nil
+}
+
+func (c *EmailChannel) Send(msg Message) error {
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	// Newlines are stripped so a product name in the subject can't inject headers
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Subject)
+	body := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		c.From, strings.Join(c.To, ", "), subject, msg.Body,
+	)
+
+	return smtp.SendMail(net.JoinHostPort(c.Host, c.Port), auth, c.From, c.To, []byte(body))
+}
@@ -0,0 +1,11 @@
+package notification
+
+import "log"
+
+// LogChannel writes messages to the application log
+type LogChannel struct{}
+
+func (LogChannel) Send(msg Message) error {
+	log.Printf("notification: %s\n%s", msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"log"
+	"os"
+	"strings"
+)
+
+// Message is a notification meant for the shop admins
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Channel delivers messages to admins. Implementations must be safe for
+// concurrent use.
+type Channel interface {
+	Send(msg Message) error
+}
+
+var channel Channel = LogChannel{}
+
+// Init selects the channel named by NOTIFICATION_CHANNEL (log or email).
+// The log channel is used when nothing, or an incomplete email setup, is configured.
+func Init() {
+	switch os.Getenv("NOTIFICATION_CHANNEL") {
+	case "email":
+		email, err := NewEmailChannelFromEnv()
+		if err != nil {
+			log.Printf("notification: %v, falling back to log channel", err)
+			return
+		}
+		SetChannel(email)
+	case "", "log":
+		SetChannel(LogChannel{})
+	default:
+		log.Printf("notification: unknown channel %q, falling back to log channel", os.Getenv("NOTIFICATION_CHANNEL"))
+	}
+}
+
+// SetChannel replaces the channel used by Notify
+func SetChannel(c Channel) {
+	channel = c
+}
+
+// Notify sends the message in the background so callers never wait on delivery.
+// Failures are logged.
+func Notify(msg Message) {
+	c := channel
+	go func() {
+		if err := c.Send(msg); err != nil {
+			log.Printf("notification: failed to send %q: %v", msg.Subject, err)
+		}
+	}()
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
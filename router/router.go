@@ -13,14 +13,29 @@ func InitializeRoutes(r *gin.Engine) {
 	// User Routes
 	publicRoutes := r.Group("/")
 	publicRoutes.Use(middleware.GlobalRateLimiter())
+	publicRoutes.Use(middleware.OptionalSignInMiddleware()) // Identify signed in callers without requiring it
 
 	{
 		publicRoutes.POST("/register", user.CreateUser)         // User registration
 		publicRoutes.POST("/signin", user.SignIn)               // User login
 		publicRoutes.GET("/products", user.GetProducts)         // Get list of products
 		publicRoutes.GET("/product/:id", user.GetProductDetail) // Get product details
+		publicRoutes.GET("/product/by-slug/:slug", user.GetProductBySlug)
+		publicRoutes.GET("/product/:id/reviews", user.GetProductReviews)
+		publicRoutes.GET("/product/:id/recommendations", user.GetProductRecommendations)
+
+		// Guest carts are identified by the X-Cart-Token header
+		publicRoutes.POST("/guest/cart", user.GuestAddToCart)
+		publicRoutes.GET("/guest/cart", user.GetGuestCart)
+		publicRoutes.GET("/guest/cart/summary", user.GetGuestCartSummary)
+		publicRoutes.DELETE("/guest/cart", user.DeleteGuestCart)
+		publicRoutes.PUT("/guest/cart", user.UpdateGuestCart)
 	}
 
+	// Sitemaps are fetched by crawlers, so they skip the rate limiter
+	r.GET("/sitemap.xml", user.GetSitemap)
+	r.GET("/sitemaps/:file", user.GetSitemapFile)
+
 	// Authenticated Routes (SignInMiddleware)
 	authRoutes := r.Group("/")
 	authRoutes.Use(middleware.SignInMiddleware()) // Ensure user is authenticated
@@ -37,12 +52,28 @@ func InitializeRoutes(r *gin.Engine) {
 	{
 		buyerRoutes.POST("/cart", user.AddToCart)
 		buyerRoutes.GET("/cart", user.GetCarts)
+		buyerRoutes.GET("/cart/summary", user.GetCartSummary)
 		buyerRoutes.DELETE("/cart", user.DeleteCart)
 		buyerRoutes.PUT("/cart", user.UpdateCart)
+		buyerRoutes.GET("/cart/recommendations", user.GetCartRecommendations)
+
+		buyerRoutes.GET("/products/recently-viewed", user.GetRecentlyViewed)
+		buyerRoutes.DELETE("/products/recently-viewed", user.ClearRecentlyViewed)
+
+		buyerRoutes.GET("/wishlist", user.GetWishlist)
+		buyerRoutes.POST("/wishlist", user.AddToWishlist)
+		buyerRoutes.DELETE("/wishlist/:product_id", user.RemoveFromWishlist)
+		buyerRoutes.POST("/wishlist/:product_id/move-to-cart", user.MoveWishlistItemToCart)
 
 		buyerRoutes.GET("/orders", user.ViewOrders)
 		buyerRoutes.GET("/order/:order_id", user.GetOrderItemsDetail)
 		buyerRoutes.POST("/order", user.PlaceOrder)
+		buyerRoutes.POST("/checkout", user.Checkout)
+		buyerRoutes.DELETE("/checkout", user.CancelCheckout)
+
+		buyerRoutes.POST("/product/:id/reviews", user.CreateReview)
+		buyerRoutes.POST("/review/:review_id/helpful", user.MarkReviewHelpful)
+		buyerRoutes.DELETE("/review/:review_id/helpful", user.UnmarkReviewHelpful)
 	}
 
 	// Admin Routes
@@ -52,10 +83,41 @@ func InitializeRoutes(r *gin.Engine) {
 		adminRoutes.POST("/signin", admin.SignIn)
 
 		adminRoutes.GET("/products", admin.GetProduct)
+		adminRoutes.GET("/products/export", admin.ExportProducts)
+		adminRoutes.POST("/products/import", admin.ImportProducts)
+		adminRoutes.GET("/products/import/:job_id", admin.GetProductImportJob)
+		adminRoutes.GET("/products/:id/stock-history", admin.GetStockHistory)
+		adminRoutes.POST("/products/:id/stock-movements", admin.AddStockMovement)
+		adminRoutes.GET("/products/:id/sale-prices", admin.GetSalePrices)
+		adminRoutes.POST("/products/:id/sale-prices", admin.CreateSalePrice)
+		adminRoutes.DELETE("/products/:id/sale-prices/:sale_id", admin.CancelSalePrice)
+		adminRoutes.GET("/products/:id/price-history", admin.GetPriceHistory)
+		adminRoutes.GET("/products/:id/components", admin.GetBundleComponents)
+		adminRoutes.PUT("/products/:id/components", admin.SetBundleComponents)
+		adminRoutes.GET("/products/:id/revisions", admin.GetProductRevisions)
+		adminRoutes.POST("/products/:id/revisions/:rev/restore", admin.RestoreProductRevision)
+		adminRoutes.GET("/inventory/reconciliation", admin.GetStockReconciliation)
+		adminRoutes.GET("/inventory/low-stock", admin.GetLowStockProducts)
+		adminRoutes.GET("/media/orphans", admin.GetOrphanedMedia)
+		adminRoutes.POST("/uploads", admin.CreateUpload)
+		adminRoutes.POST("/uploads/:id/confirm", admin.ConfirmUpload)
 		adminRoutes.GET("/product/:id", admin.GetProductDetail)
 		adminRoutes.POST("/product", admin.AddProduct)
 		adminRoutes.DELETE("/product/:id", admin.AdminDeleteProduct)
+		adminRoutes.POST("/product/:id/restore", admin.AdminRestoreProduct)
+		adminRoutes.DELETE("/product/:id/purge", admin.AdminPurgeProduct)
 		adminRoutes.PUT("/product/:id", admin.AdminEditProduct)
+		adminRoutes.POST("/product/:id/images", admin.AddProductImages)
+		adminRoutes.PUT("/product/:id/images/reorder", admin.ReorderProductImages)
+		adminRoutes.PUT("/product/:id/images/:image_id", admin.UpdateProductImage)
+		adminRoutes.DELETE("/product/:id/images/:image_id", admin.DeleteProductImage)
+
+		adminRoutes.GET("/category/:id/attributes", admin.GetCategoryAttributes)
+		adminRoutes.PUT("/category/:id/attributes", admin.UpdateCategoryAttributes)
+
+		adminRoutes.GET("/reviews", admin.GetReviews)
+		adminRoutes.PUT("/review/:id/hide", admin.HideReview)
+		adminRoutes.PUT("/review/:id/unhide", admin.UnhideReview)
 
 		adminRoutes.GET("/orders", admin.ViewOrders)
 		adminRoutes.GET("/order/:order_id", admin.GetOrderItemsDetail)
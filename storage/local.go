@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps objects on disk. The API serves Dir under /uploads and
+// accepts signed uploads there through UploadHandler.
+type LocalStorage struct {
+	Dir     string
+	BaseURL string // Public URL Dir is served from
+	Secret  []byte // Signs upload URLs
+}
+
+// NewLocalStorageFromEnv reads STORAGE_LOCAL_DIR, STORAGE_LOCAL_URL and
+// STORAGE_LOCAL_SECRET, defaulting to ./uploads served at
+// http://localhost:8080/uploads. Without a secret, upload URLs only stay
+// valid until the process restarts.
+func NewLocalStorageFromEnv() *LocalStorage {
+	s := &LocalStorage{
+		Dir:     os.Getenv("STORAGE_LOCAL_DIR"),
+		BaseURL: os.Getenv("STORAGE_LOCAL_URL"),
+		Secret:  []byte(os.Getenv("STORAGE_LOCAL_SECRET")),
+	}
+	if s.Dir == "" {
+		s.Dir = "uploads"
+	}
+	if s.BaseURL == "" {
+		s.BaseURL = "http://localhost:8080/uploads"
+	}
+	if len(s.Secret) == 0 {
+		s.Secret = make([]byte, 32)
+		rand.Read(s.Secret)
+	}
+	return s
+}
+
+func (s *LocalStorage) Put(key string, body io.Reader, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Writing to a temporary file first means readers never see half an object
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) PublicURL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + escapeKey(key)
+}
+
+// SignedURL is the public URL, local objects are readable by anyone
+func (s *LocalStorage) SignedURL(key string, expires time.Duration) (string, error) {
+	return s.PublicURL(key), nil
+}
+
+func (s *LocalStorage) SignedUploadURL(key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	return s.PublicURL(key) + "?expires=" + expiresAt + "&signature=" + s.uploadSignature(key, expiresAt), nil
+}
+
+// UploadHandler stores the body of PUT requests to signed upload URLs. It
+// reads the key from the request path, so mount it behind http.StripPrefix.
+func (s *LocalStorage) UploadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		expiresAt := r.URL.Query().Get("expires")
+		expires, err := strconv.ParseInt(expiresAt, 10, 64)
+		signature := r.URL.Query().Get("signature")
+		if err != nil || time.Now().Unix() > expires ||
+			!hmac.Equal([]byte(signature), []byte(s.uploadSignature(key, expiresAt))) {
+			http.Error(w, "invalid or expired upload URL", http.StatusForbidden)
+			return
+		}
+
+		if err := s.Put(key, r.Body, r.Header.Get("Content-Type")); err != nil {
+			http.Error(w, "failed to store object", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *LocalStorage) uploadSignature(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(http.MethodPut + "\n" + key + "\n" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to a file inside Dir, rejecting keys that would escape it
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", errors.New("storage: invalid object key")
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)), nil
+}
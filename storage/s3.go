@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPresignExpiry is the longest lifetime S3 accepts for a presigned URL
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// S3Storage keeps objects in an S3 compatible bucket such as AWS S3 or MinIO.
+// Requests use path style addressing and AWS Signature Version 4.
+type S3Storage struct {
+	Endpoint      string // Scheme and host, e.g. https://s3.eu-west-1.amazonaws.com
+	Region        string
+	Bucket        string
+	AccessKey     string
+	SecretKey     string
+	PublicBaseURL string // Optional base URL objects are publicly read from, such as a CDN
+
+	client *http.Client
+}
+
+// NewS3StorageFromEnv reads STORAGE_S3_ENDPOINT, STORAGE_S3_REGION,
+// STORAGE_S3_BUCKET, STORAGE_S3_ACCESS_KEY, STORAGE_S3_SECRET_KEY and
+// STORAGE_S3_PUBLIC_URL
+func NewS3StorageFromEnv() (*S3Storage, error) {
+	s := &S3Storage{
+		Endpoint:      strings.TrimSuffix(os.Getenv("STORAGE_S3_ENDPOINT"), "/"),
+		Region:        os.Getenv("STORAGE_S3_REGION"),
+		Bucket:        os.Getenv("STORAGE_S3_BUCKET"),
+		AccessKey:     os.Getenv("STORAGE_S3_ACCESS_KEY"),
+		SecretKey:     os.Getenv("STORAGE_S3_SECRET_KEY"),
+		PublicBaseURL: strings.TrimSuffix(os.Getenv("STORAGE_S3_PUBLIC_URL"), "/"),
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.Region == "" {
+		s.Region = "us-east-1"
+	}
+	if s.Endpoint == "" || s.Bucket == "" {
+		return nil, errors.New("STORAGE_S3_ENDPOINT and STORAGE_S3_BUCKET are required for S3 storage")
+	}
+	if s.AccessKey == "" || s.SecretKey == "" {
+		return nil, errors.New("STORAGE_S3_ACCESS_KEY and STORAGE_S3_SECRET_KEY are required for S3 storage")
+	}
+	return s, nil
+}
+
+func (s *S3Storage) Put(key string, body io.Reader, contentType string) error {
+	// The payload hash is part of the signature, so the object is read up front
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, sha256Hex(data), time.Now())
+
+	return s.do(req, http.StatusOK)
+}
+
+func (s *S3Storage) Get(key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, sha256Hex(nil), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("storage: %s %s: status code %d, response: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+}
+
+func (s *S3Storage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, sha256Hex(nil), time.Now())
+
+	// S3 answers 204 whether or not the object existed
+	return s.do(req, http.StatusNoContent, http.StatusNotFound)
+}
+
+func (s *S3Storage) PublicURL(key string) string {
+	if s.PublicBaseURL != "" {
+		return s.PublicBaseURL + "/" + escapeKey(key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Storage) SignedURL(key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expires, time.Now())
+}
+
+func (s *S3Storage) SignedUploadURL(key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, expires, time.Now())
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	return s.Endpoint + s.objectPath(key)
+}
+
+func (s *S3Storage) objectPath(key string) string {
+	return "/" + escapeKey(s.Bucket) + "/" + escapeKey(key)
+}
+
+func (s *S3Storage) do(req *http.Request, okStatuses ...int) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("storage: %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	for _, status := range okStatuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("storage: %s %s: status code %d, response: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+}
+
+// sign adds a Signature Version 4 Authorization header to the request
+func (s *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := s.scope(now)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, s.signature(now, amzDate, scope, canonicalRequest),
+	))
+}
+
+// presign builds a URL that carries its Signature Version 4 in the query string
+func (s *S3Storage) presign(method, key string, expires time.Duration, now time.Time) (string, error) {
+	if expires <= 0 || expires > maxPresignExpiry {
+		return "", fmt.Errorf("storage: presigned URLs must expire within %s", maxPresignExpiry)
+	}
+
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := s.scope(now)
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.AccessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expires.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = uriEncode(name, true) + "=" + uriEncode(query[name], true)
+	}
+	canonicalQuery := strings.Join(params, "&")
+
+	path := s.objectPath(key)
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+	return s.Endpoint + path + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.UTC().Format("20060102") + "/" + s.Region + "/s3/aws4_request"
+}
+
+func (s *S3Storage) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), now.UTC().Format("20060102"))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapeKey percent-encodes an object key for use in a URL path
+func escapeKey(key string) string {
+	return uriEncode(key, false)
+}
+
+// uriEncode encodes everything but the RFC 3986 unreserved characters, and
+// slashes unless encodeSlash is set, as Signature Version 4 requires
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// supabaseFolder is the folder of the bucket uploads have always been kept in
+const supabaseFolder = "uploads"
+
+// SupabaseStorage keeps objects in a Supabase Storage bucket
+type SupabaseStorage struct {
+	URL    string
+	APIKey string
+	Bucket string
+
+	client *resty.Client
+}
+
+// NewSupabaseStorageFromEnv reads SUPABASE_URL, SUPABASE_API_KEY and SUPABASE_BUCKET
+func NewSupabaseStorageFromEnv() (*SupabaseStorage, error) {
+	s := &SupabaseStorage{
+		URL:    os.Getenv("SUPABASE_URL"),
+		APIKey: os.Getenv("SUPABASE_API_KEY"),
+		Bucket: os.Getenv("SUPABASE_BUCKET"),
+		client: resty.New().SetTimeout(30 * time.Second),
+	}
+	if s.URL == "" || s.APIKey == "" || s.Bucket == "" {
+		return nil, errors.New("SUPABASE_URL, SUPABASE_API_KEY and SUPABASE_BUCKET are required for Supabase storage")
+	}
+	return s, nil
+}
+
+func (s *SupabaseStorage) Put(key string, body io.Reader, contentType string) error {
+	resp, err := s.request().
+		SetHeader("Content-Type", contentType).
+		SetHeader("x-upsert", "true").
+		SetBody(body).
+		Post(fmt.Sprintf("%s/storage/v1/object/%s", s.URL, s.objectPath(key)))
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return fmt.Errorf("failed to upload object, status code: %d, response: %s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
+
+func (s *SupabaseStorage) Get(key string) (io.ReadCloser, error) {
+	resp, err := s.request().
+		SetDoNotParseResponse(true).
+		Get(fmt.Sprintf("%s/storage/v1/object/authenticated/%s", s.URL, s.objectPath(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %v", err)
+	}
+
+	body := resp.RawBody()
+	if resp.StatusCode() == http.StatusOK {
+		return body, nil
+	}
+	defer body.Close()
+	message, _ := io.ReadAll(io.LimitReader(body, 1024))
+	// Missing objects are reported as a 400 with a not_found error
+	if resp.StatusCode() == http.StatusNotFound || strings.Contains(string(message), "not_found") {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("failed to download object, status code: %d, response: %s", resp.StatusCode(), message)
+}
+
+func (s *SupabaseStorage) Delete(key string) error {
+	resp, err := s.request().
+		Delete(fmt.Sprintf("%s/storage/v1/object/%s", s.URL, s.objectPath(key)))
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("failed to delete object, status code: %d, response: %s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
+
+func (s *SupabaseStorage) PublicURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/object/public/%s", s.URL, s.objectPath(key))
+}
+
+func (s *SupabaseStorage) SignedURL(key string, expires time.Duration) (string, error) {
+	var result struct {
+		SignedURL string `json:"signedURL"`
+	}
+	resp, err := s.request().
+		SetBody(map[string]int{"expiresIn": int(expires.Seconds())}).
+		SetResult(&result).
+		Post(fmt.Sprintf("%s/storage/v1/object/sign/%s", s.URL, s.objectPath(key)))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign object URL: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK || result.SignedURL == "" {
+		return "", fmt.Errorf("failed to sign object URL, status code: %d, response: %s", resp.StatusCode(), resp.String())
+	}
+
+	// The signed path is relative to the storage API
+	return s.URL + "/storage/v1" + result.SignedURL, nil
+}
+
+// SignedUploadURL signs an upload URL, which Supabase keeps valid for two
+// hours whatever expires asks for
+func (s *SupabaseStorage) SignedUploadURL(key string, expires time.Duration) (string, error) {
+	var result struct {
+		URL string `json:"url"`
+	}
+	resp, err := s.request().
+		SetResult(&result).
+		Post(fmt.Sprintf("%s/storage/v1/object/upload/sign/%s", s.URL, s.objectPath(key)))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign upload URL: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK || result.URL == "" {
+		return "", fmt.Errorf("failed to sign upload URL, status code: %d, response: %s", resp.StatusCode(), resp.String())
+	}
+
+	// The signed path is relative to the storage API
+	return s.URL + "/storage/v1" + result.URL, nil
+}
+
+func (s *SupabaseStorage) request() *resty.Request {
+	return s.client.R().SetHeader("Authorization", fmt.Sprintf("Bearer %s", s.APIKey))
+}
+
+func (s *SupabaseStorage) objectPath(key string) string {
+	return escapeKey(s.Bucket) + "/" + supabaseFolder + "/" + escapeKey(key)
+}
@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+// ErrInvalidImage is returned for uploads that are too large or not an allowed image
+var ErrInvalidImage = errors.New("invalid image")
+
+// allowedImageTypes are the sniffed content types accepted for upload,
+// whatever the file name or the declared content type say
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// IsAllowedImageType reports whether images of contentType may be uploaded
+func IsAllowedImageType(contentType string) bool {
+	return allowedImageTypes[contentType]
+}
+
+// maxImagePixels rejects images that are small on disk but huge once decoded
+const maxImagePixels = 50_000_000
+
+// Rendition sizes by longest side in pixels, images are never upscaled
+const (
+	thumbnailMaxSide = 200
+	mediumMaxSide    = 600
+	largeMaxSide     = 1600
+)
+
+// ProcessedImage holds the WebP encoded renditions of an upload
+type ProcessedImage struct {
+	Thumbnail []byte
+	Medium    []byte
+	Large     []byte
+}
+
+// ReadImage reads an upload, failing once it grows past maxBytes
+func ReadImage(r io.Reader, maxBytes int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: larger than %d MB", ErrInvalidImage, maxBytes>>20)
+	}
+	return data, nil
+}
+
+// ProcessImage validates an upload and encodes its renditions as WebP.
+// Re-encoding drops EXIF and any other metadata, so the EXIF orientation
+// is applied to the pixels first.
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	if !allowedImageTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s is not an allowed image type", ErrInvalidImage, contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels is too large", ErrInvalidImage, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if contentType == "image/jpeg" {
+		img = _orientImage(img, _exifOrientation(data))
+	}
+
+	var processed ProcessedImage
+	for _, rendition := range []struct {
+		maxSide int
+		data    *[]byte
+	}{
+		{thumbnailMaxSide, &processed.Thumbnail},
+		{mediumMaxSide, &processed.Medium},
+		{largeMaxSide, &processed.Large},
+	} {
+		var buf bytes.Buffer
+		if err := nativewebp.Encode(&buf, _fitImage(img, rendition.maxSide), nil); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %v", err)
+		}
+		*rendition.data = buf.Bytes()
+	}
+	return &processed, nil
+}
+
+// _fitImage scales an image down so its longest side is at most maxSide
+func _fitImage(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height, width = max(1, height*maxSide/width), maxSide
+	} else {
+		width, height = max(1, width*maxSide/height), maxSide
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// _orientImage turns the pixels upright for an EXIF orientation between 1 and 8
+func _orientImage(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if orientation >= 5 {
+		// Orientations 5 to 8 swap width and height
+		dst = image.NewNRGBA(image.Rect(0, 0, height, width))
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := x, y
+			switch orientation {
+			case 2:
+				dx = width - 1 - x
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dy = height - 1 - y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// _exifOrientation reads the orientation tag of a JPEG, 1 when there is none
+func _exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		marker := data[i+1]
+		// Metadata segments all come before the start of scan
+		if data[i] != 0xFF || marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return _tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// _tiffOrientation looks up the orientation tag in the first IFD of EXIF data
+func _tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
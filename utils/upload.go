@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"deketna/config"
+	"deketna/models"
+	"deketna/storage"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// UploadedImage holds the public URLs of the renditions of an uploaded image
+type UploadedImage struct {
+	URL          string // Large rendition
+	MediumURL    string
+	ThumbnailURL string
+
+	SourceHash string   // SHA-256 of the uploaded file, which names the renditions
+	Keys       []string // Storage keys of the renditions, for media references
+}
+
+// StoredImage is the image whose renditions were stored for the file with sourceHash
+func StoredImage(sourceHash string) UploadedImage {
+	thumbnailKey := fmt.Sprintf("images/%s/thumbnail.webp", sourceHash)
+	mediumKey := fmt.Sprintf("images/%s/medium.webp", sourceHash)
+	largeKey := fmt.Sprintf("images/%s/large.webp", sourceHash)
+	return UploadedImage{
+		URL:          storage.PublicURL(largeKey),
+		MediumURL:    storage.PublicURL(mediumKey),
+		ThumbnailURL: storage.PublicURL(thumbnailKey),
+		SourceHash:   sourceHash,
+		Keys:         []string{thumbnailKey, mediumKey, largeKey},
+	}
+}
+
+// UploadFormImage processes an image posted in a multipart form and stores
+// every rendition, returning their public URLs
+func UploadFormImage(file *multipart.FileHeader) (UploadedImage, error) {
+	src, err := file.Open()
+	if err != nil {
+		return UploadedImage{}, fmt.Errorf("failed to read image: %v", err)
+	}
+	defer src.Close()
+
+	return StoreImage(src, file.Filename)
+}
+
+// StoreImage validates an image and stores every rendition. Renditions are
+// keyed by the hash of the file, so storing the same file again reuses them.
+func StoreImage(r io.Reader, originalFilename string) (UploadedImage, error) {
+	data, err := ReadImage(r, config.MaxImageUploadBytes())
+	if err != nil {
+		return UploadedImage{}, err
+	}
+	sum := sha256.Sum256(data)
+	uploaded := StoredImage(hex.EncodeToString(sum[:]))
+
+	// The same file was processed and stored before
+	reused, err := models.ReuseMedia(config.DB, uploaded.Keys)
+	if err != nil {
+		return UploadedImage{}, fmt.Errorf("failed to look up stored image: %v", err)
+	}
+	if reused {
+		return uploaded, nil
+	}
+
+	processed, err := ProcessImage(data)
+	if err != nil {
+		return UploadedImage{}, err
+	}
+
+	// The client's file name is kept for reference only, never as a key
+	originalFilename = filepath.Base(originalFilename)
+	if len(originalFilename) > 255 {
+		originalFilename = strings.ToValidUTF8(originalFilename[:255], "")
+	}
+
+	for i, rendition := range [][]byte{processed.Thumbnail, processed.Medium, processed.Large} {
+		key := uploaded.Keys[i]
+		if err := storage.Put(key, bytes.NewReader(rendition), "image/webp"); err != nil {
+			return UploadedImage{}, fmt.Errorf("failed to store image: %v", err)
+		}
+		// Concurrent uploads of the same file store the same bytes, the first record wins
+		if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.MediaObject{
+			Key:              key,
+			SourceHash:       uploaded.SourceHash,
+			ContentType:      "image/webp",
+			Size:             int64(len(rendition)),
+			OriginalFilename: originalFilename,
+		}).Error; err != nil {
+			return UploadedImage{}, fmt.Errorf("failed to record stored image: %v", err)
+		}
+	}
+	return uploaded, nil
+}